@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/hpa"
+	"github.com/porgull/go-search/pkg/search"
+	"github.com/spf13/cobra"
+)
+
+type abstractFlagsCfg struct {
+	on          string
+	load        string
+	clusterSize int
+	out         string
+}
+
+var (
+	abstractFlags = &abstractFlagsCfg{}
+)
+
+var (
+	abstractCmd = &cobra.Command{
+		Use:   "abstract (--on <environment>|--load <env.json>) --out <abstraction.json>",
+		Short: "abstract allows you to precompute the abstraction of a grid environment used by hpa*.",
+		Long:  "abstract allows you to precompute the abstraction of a grid environment used by hpa*, which can then be passed to it with --params abstraction=<abstraction.json>.",
+		Run: func(cmd *cobra.Command, args []string) {
+			env := loadEnvironment(cmd, abstractFlags.on, abstractFlags.load, false)
+
+			grid, ok := env.(*environments.GridEnvironment)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Environment %s is not a grid\n", env.Name())
+				os.Exit(1)
+			}
+
+			if abstractFlags.out == "" {
+				cmd.Help()
+				os.Exit(1)
+			}
+
+			abstraction, err := hpa.Build(search.Context{}, grid, abstractFlags.clusterSize)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not build abstraction of %s: %s\n", env.Name(), err.Error())
+				os.Exit(1)
+			}
+
+			f, err := os.Create(abstractFlags.out)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not create abstraction file at %s: %s\n", abstractFlags.out, err.Error())
+				os.Exit(1)
+			}
+
+			if err = abstraction.Save(f); err != nil {
+				f.Close()
+				fmt.Fprintf(os.Stderr, "Could not save abstraction to %s: %s\n", abstractFlags.out, err.Error())
+				os.Exit(1)
+			}
+			f.Close()
+
+			fmt.Printf("Saved abstraction with %d nodes and %d edges to %s\n", len(abstraction.Nodes), abstraction.EdgeCount(), abstractFlags.out)
+		},
+	}
+)
+
+func init() {
+	abstractCmd.PersistentFlags().StringVar(&abstractFlags.on, "on", "", "Use this pre-created environment to abstract")
+	abstractCmd.PersistentFlags().StringVar(&abstractFlags.load, "load", "", "Load your own environment into memory")
+	abstractCmd.PersistentFlags().IntVar(&abstractFlags.clusterSize, "cluster-size", 10, "Width and height of the clusters the grid is partitioned into")
+	abstractCmd.PersistentFlags().StringVar(&abstractFlags.out, "out", "", "File to save the abstraction to")
+}
+
+func init() {
+	rootCmd.AddCommand(abstractCmd)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/porgull/go-search/pkg/algorithms"
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+// runCheckpointed steps through the algorithm in --with, resuming
+// from --resume if it's set, and saving a checkpoint to
+// --checkpoint every --checkpoint-every and when interrupted
+func runCheckpointed(env environments.Environment) {
+	stepper, err := algorithms.GetStepper(runFlags.with)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not get algorithm %s: %s\n", runFlags.with, err.Error())
+		os.Exit(1)
+	}
+
+	checkpointer, ok := stepper.(algorithms.Checkpointer)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Algorithm %s can't be checkpointed\n", runFlags.with)
+		os.Exit(1)
+	}
+
+	ctx := search.Context{
+		CustomSearchParams: search.CustomSearchParams(runFlags.customSearchParams),
+	}
+
+	if runFlags.resume != "" {
+		checkpoint := loadCheckpoint(runFlags.resume)
+		if err = checkpointer.Resume(ctx, env, checkpoint); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not resume from checkpoint %s: %s\n", runFlags.resume, err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("Resumed from checkpoint %s after %d iterations.\n", runFlags.resume, checkpoint.Iterations)
+	} else if err = checkpointer.Start(ctx, env); err != nil {
+		fmt.Fprintf(os.Stderr, "Error while running algorithm %s on %s: %s\n", runFlags.with, env.Name(), err.Error())
+		os.Exit(1)
+	}
+
+	// interrupting the search saves a checkpoint
+	// if there's somewhere to save it
+	interrupted := make(chan os.Signal, 1)
+	if runFlags.checkpoint != "" {
+		signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(interrupted)
+	}
+
+	lastSaved := time.Now()
+	for {
+		if _, done := checkpointer.Step(); done {
+			break
+		}
+
+		select {
+		case <-interrupted:
+			saveCheckpoint(checkpointer, runFlags.checkpoint)
+			fmt.Printf("Interrupted; saved checkpoint to %s.\n", runFlags.checkpoint)
+			os.Exit(1)
+		default:
+		}
+
+		if runFlags.checkpoint != "" && time.Since(lastSaved) >= runFlags.checkpointEvery {
+			saveCheckpoint(checkpointer, runFlags.checkpoint)
+			lastSaved = time.Now()
+		}
+	}
+
+	result, err := checkpointer.Result()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error while running algorithm %s on %s: %s\n", runFlags.with, env.Name(), err.Error())
+		os.Exit(1)
+	}
+
+	result.Print()
+}
+
+// loadCheckpoint loads the checkpoint file, exiting on errors
+func loadCheckpoint(path string) *algorithms.Checkpoint {
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not open checkpoint file at %s: %s\n", path, err.Error())
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	checkpoint, err := algorithms.LoadCheckpoint(f)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not load checkpoint from %s: %s\n", path, err.Error())
+		os.Exit(1)
+	}
+	return checkpoint
+}
+
+// saveCheckpoint saves a checkpoint of the search to the file,
+// replacing it only once the checkpoint is fully written, so
+// the last one survives if the machine dies while saving
+func saveCheckpoint(checkpointer algorithms.Checkpointer, path string) {
+	checkpoint, err := checkpointer.Checkpoint()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not checkpoint search: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	f, err := os.Create(path + ".tmp")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not create checkpoint file at %s: %s\n", path+".tmp", err.Error())
+		os.Exit(1)
+	}
+
+	if err = checkpoint.Save(f); err == nil {
+		err = f.Sync()
+	}
+	f.Close()
+	if err == nil {
+		err = os.Rename(path+".tmp", path)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not save checkpoint to %s: %s\n", path, err.Error())
+		os.Exit(1)
+	}
+}
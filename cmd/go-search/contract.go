@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/porgull/go-search/pkg/ch"
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/spf13/cobra"
+)
+
+type contractFlagsCfg struct {
+	on   string
+	load string
+	out  string
+}
+
+var (
+	contractFlags = &contractFlagsCfg{}
+)
+
+var (
+	contractCmd = &cobra.Command{
+		Use:   "contract (--on <environment>|--load <env.json>) --out <hierarchy.json>",
+		Short: "contract allows you to precompute the contraction hierarchy of a state environment for fast queries.",
+		Long:  "contract allows you to precompute the contraction hierarchy of a state environment, which can then be loaded by the query command to quickly answer many shortest path queries.",
+		Run: func(cmd *cobra.Command, args []string) {
+			env := loadEnvironment(cmd, contractFlags.on, contractFlags.load, false)
+
+			var states environments.States
+			switch e := env.(type) {
+			case *environments.StateEnvironment:
+				states = e.States
+			case *environments.MDPEnvironment:
+				states = e.StateEnvironment.States
+			default:
+				fmt.Fprintf(os.Stderr, "Environment %s is not a state environment\n", env.Name())
+				os.Exit(1)
+			}
+
+			if contractFlags.out == "" {
+				cmd.Help()
+				os.Exit(1)
+			}
+
+			hierarchy := ch.Build(states)
+
+			f, err := os.Create(contractFlags.out)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not create hierarchy file at %s: %s\n", contractFlags.out, err.Error())
+				os.Exit(1)
+			}
+
+			if err = hierarchy.Save(f); err != nil {
+				f.Close()
+				fmt.Fprintf(os.Stderr, "Could not save hierarchy to %s: %s\n", contractFlags.out, err.Error())
+				os.Exit(1)
+			}
+			f.Close()
+
+			fmt.Printf("Saved hierarchy of %d states with %d shortcuts to %s\n", len(hierarchy.Names), hierarchy.Shortcuts(), contractFlags.out)
+		},
+	}
+)
+
+func init() {
+	contractCmd.PersistentFlags().StringVar(&contractFlags.on, "on", "", "Use this pre-created environment to contract")
+	contractCmd.PersistentFlags().StringVar(&contractFlags.load, "load", "", "Load your own environment into memory")
+	contractCmd.PersistentFlags().StringVar(&contractFlags.out, "out", "", "File to save the hierarchy to")
+}
+
+func init() {
+	rootCmd.AddCommand(contractCmd)
+}
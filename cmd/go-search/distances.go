@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/shortestpath"
+	"github.com/spf13/cobra"
+)
+
+type distancesFlagsCfg struct {
+	on   string
+	load string
+	with string
+	from string
+	out  string
+}
+
+var (
+	distancesFlags = &distancesFlagsCfg{}
+)
+
+var (
+	distancesCmd = &cobra.Command{
+		Use:   "distances (--on <environment>|--load <env.json>) [--with dijkstra|bellman_ford|floyd_warshall] [--from <state>] [--out <distances.csv>]",
+		Short: "distances allows you to compute the shortest distances between states as a CSV matrix.",
+		Long:  "distances allows you to compute the shortest distances between every pair of states of a state environment, or from the --from state only, and prints them as a CSV matrix with a row per source and a column per target. Other environments only support dijkstra from their start node.",
+		Run: func(cmd *cobra.Command, args []string) {
+			env := loadEnvironment(cmd, distancesFlags.on, distancesFlags.load, distancesFlags.with != "dijkstra")
+
+			var stateEnv *environments.StateEnvironment
+			switch e := env.(type) {
+			case *environments.StateEnvironment:
+				stateEnv = e
+			case *environments.MDPEnvironment:
+				stateEnv = &e.StateEnvironment
+			}
+
+			matrix, err := distanceMatrix(env, stateEnv)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not compute distances: %s\n", err.Error())
+				os.Exit(1)
+			}
+
+			var out io.Writer = os.Stdout
+			if distancesFlags.out != "" {
+				f, err := os.Create(distancesFlags.out)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Could not create distances file at %s: %s\n", distancesFlags.out, err.Error())
+					os.Exit(1)
+				}
+				defer f.Close()
+				out = f
+			}
+
+			if err := matrix.WriteCSV(out); err != nil {
+				fmt.Fprintf(os.Stderr, "Could not write distances: %s\n", err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+// distanceMatrix runs the chosen algorithm from every
+// source, which is only the start node if the
+// environment isn't a state environment
+func distanceMatrix(env environments.Environment, stateEnv *environments.StateEnvironment) (*shortestpath.Matrix, error) {
+	if stateEnv == nil {
+		if distancesFlags.with != "dijkstra" || distancesFlags.from != "" {
+			return nil, fmt.Errorf("environment %s is not a state environment, so only supports dijkstra from its start node", env.Name())
+		}
+
+		tree, err := shortestpath.Dijkstra(env)
+		if err != nil {
+			return nil, err
+		}
+		return shortestpath.NewMatrix([]*shortestpath.Tree{tree}, tree.Reachable()), nil
+	}
+
+	names := make([]string, 0, len(stateEnv.States))
+	for name := range stateEnv.States {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sources := names
+	if distancesFlags.from != "" {
+		if _, ok := stateEnv.States[distancesFlags.from]; !ok {
+			return nil, fmt.Errorf("state %s missing from states", distancesFlags.from)
+		}
+		sources = []string{distancesFlags.from}
+	}
+
+	trees := make([]*shortestpath.Tree, len(sources))
+	for i, source := range sources {
+		var err error
+		switch distancesFlags.with {
+		case "dijkstra":
+			fromSource := *stateEnv
+			fromSource.StartNode = source
+			trees[i], err = shortestpath.Dijkstra(&fromSource)
+		case "bellman_ford":
+			trees[i], err = shortestpath.BellmanFord(stateEnv.States, source)
+		case "floyd_warshall":
+			matrix, err := shortestpath.FloydWarshall(stateEnv.States)
+			if err != nil || distancesFlags.from == "" {
+				return matrix, err
+			}
+
+			// only keep the row of the source
+			distances := map[string]float64{}
+			for _, target := range names {
+				if distance, ok := matrix.Distance(source, target); ok {
+					distances[target] = distance
+				}
+			}
+			trees[i] = &shortestpath.Tree{Source: source, Distances: distances}
+		default:
+			return nil, fmt.Errorf("unknown algorithm %s, expected dijkstra, bellman_ford or floyd_warshall", distancesFlags.with)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return shortestpath.NewMatrix(trees, names), nil
+}
+
+func init() {
+	distancesCmd.PersistentFlags().StringVar(&distancesFlags.on, "on", "", "Use this pre-created environment to compute distances in")
+	distancesCmd.PersistentFlags().StringVar(&distancesFlags.load, "load", "", "Load your own environment into memory")
+	distancesCmd.PersistentFlags().StringVar(&distancesFlags.with, "with", "floyd_warshall", "Algorithm to compute distances with: dijkstra, bellman_ford or floyd_warshall")
+	distancesCmd.PersistentFlags().StringVar(&distancesFlags.from, "from", "", "Only compute the distances from this state")
+	distancesCmd.PersistentFlags().StringVar(&distancesFlags.out, "out", "", "File to write the distances to, instead of stdout")
+}
+
+func init() {
+	rootCmd.AddCommand(distancesCmd)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/porgull/go-search/pkg/algorithms"
+	"github.com/porgull/go-search/pkg/search"
+	"github.com/spf13/cobra"
+)
+
+type enumerateFlagsCfg struct {
+	on          string
+	load        string
+	dir         string
+	memoryLimit int
+}
+
+var (
+	enumerateFlags = &enumerateFlagsCfg{}
+)
+
+var (
+	enumerateCmd = &cobra.Command{
+		Use:   "enumerate (--on <environment>|--load <env.json>) [--dir <layers dir>] [--memory-limit <nodes>]",
+		Short: "enumerate allows you to find every state reachable from the start of an environment.",
+		Long:  "enumerate allows you to find every state reachable from the start of an environment with a breadth first search that writes each layer to disk, so it can enumerate state spaces larger than RAM. It prints the size of every layer as it's found, then the number of reachable states and the depth of the deepest one. The layers are kept in --dir if it's set.",
+		Run: func(cmd *cobra.Command, args []string) {
+			env := loadEnvironment(cmd, enumerateFlags.on, enumerateFlags.load, true)
+
+			// interrupting the enumeration cancels it,
+			// so its scratch files are cleaned up
+			interrupted := make(chan os.Signal, 1)
+			signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+			defer signal.Stop(interrupted)
+			done := make(chan struct{})
+			go func() {
+				<-interrupted
+				close(done)
+			}()
+
+			enumeration, err := algorithms.Enumerate(search.Context{Done: done}, env, algorithms.EnumerateConfig{
+				Dir:         enumerateFlags.dir,
+				MemoryLimit: enumerateFlags.memoryLimit,
+				OnLayer: func(depth, size int) {
+					fmt.Printf("Depth %d: %d states\n", depth, size)
+				},
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not enumerate %s: %s\n", env.Name(), err.Error())
+				os.Exit(1)
+			}
+
+			fmt.Printf("Found %d reachable states, up to %d steps from the start.\n", enumeration.States, enumeration.Diameter)
+			if enumerateFlags.dir != "" {
+				fmt.Printf("Wrote layers 0-%d to %s.\n", enumeration.Diameter, enumerateFlags.dir)
+			}
+		},
+	}
+)
+
+func init() {
+	enumerateCmd.PersistentFlags().StringVar(&enumerateFlags.on, "on", "", "Use this pre-created environment to enumerate")
+	enumerateCmd.PersistentFlags().StringVar(&enumerateFlags.load, "load", "", "Load your own environment into memory")
+	enumerateCmd.PersistentFlags().StringVar(&enumerateFlags.dir, "dir", "", "Directory to write the layers to as layer-<depth> files, instead of a temporary one")
+	enumerateCmd.PersistentFlags().IntVar(&enumerateFlags.memoryLimit, "memory-limit", 1<<20, "Number of states to keep in memory before spilling them to disk")
+}
+
+func init() {
+	rootCmd.AddCommand(enumerateCmd)
+}
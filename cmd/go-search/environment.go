@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/spf13/cobra"
+)
+
+// loadEnvironment loads and validates either the pre-made
+// environment or the environment file, exiting on errors.
+// Negative costs are only valid if the command supports them
+func loadEnvironment(cmd *cobra.Command, on, load string, negativeCosts bool) environments.Environment {
+	var env environments.Environment
+	var err error
+
+	if on == "" && load == "" {
+		cmd.Help()
+		os.Exit(1)
+	} else if on != "" && load != "" {
+		cmd.Help()
+		os.Exit(1)
+	} else if on != "" {
+		env, err = environments.GetEnvironment(on)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not get pre-made environment %s: %s\n", on, err.Error())
+			os.Exit(1)
+		}
+	} else if load != "" {
+		f, err := os.Open(load)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not open env file at %s: %s", load, err.Error())
+			os.Exit(1)
+		}
+
+		env, err = environments.LoadEnvironmentFrom(f)
+		if err != nil {
+			f.Close()
+			fmt.Fprintf(os.Stderr, "Could not load environment from %s: %s\n", load, err.Error())
+			os.Exit(1)
+		}
+		f.Close()
+	}
+
+	var negativeCost *environments.NegativeCostError
+	if err = env.Validate(); errors.As(err, &negativeCost) && !negativeCosts {
+		fmt.Fprintf(os.Stderr, "Invalid environment: %s (negative costs need bellman_ford)\n", err.Error())
+		os.Exit(1)
+	} else if err != nil && negativeCost == nil {
+		fmt.Fprintf(os.Stderr, "Invalid environment: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	return env
+}
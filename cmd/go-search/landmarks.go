@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/landmarks"
+)
+
+// useLandmarks replaces the heuristic of the state environment
+// with the landmark heuristic, where the landmarks are either
+// picked by the strategy (farthest or random) or listed
+func useLandmarks(env environments.Environment, strategy string, count int, seed int64) ([]string, error) {
+	var stateEnv *environments.StateEnvironment
+	switch e := env.(type) {
+	case *environments.StateEnvironment:
+		stateEnv = e
+	case *environments.MDPEnvironment:
+		stateEnv = &e.StateEnvironment
+	default:
+		return nil, fmt.Errorf("landmarks can only be used with state environments")
+	}
+
+	var names []string
+	switch strategy {
+	case "farthest":
+		names = landmarks.Farthest(stateEnv.States, stateEnv.StartNode, count)
+	case "random":
+		names = landmarks.Random(stateEnv.States, count, seed)
+	default:
+		names = strings.Split(strategy, ",")
+	}
+
+	l, err := landmarks.Compute(stateEnv.States, names)
+	if err != nil {
+		return nil, err
+	}
+
+	stateEnv.UseHeuristic(l.HeuristicTo(stateEnv.GoalNode))
+	return names, nil
+}
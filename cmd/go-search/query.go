@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/porgull/go-search/pkg/ch"
+	"github.com/porgull/go-search/pkg/search"
+	"github.com/spf13/cobra"
+)
+
+type queryFlagsCfg struct {
+	hierarchy string
+	pairs     string
+}
+
+var (
+	queryFlags = &queryFlagsCfg{}
+)
+
+var (
+	queryCmd = &cobra.Command{
+		Use:   "query --hierarchy <hierarchy.json> [--pairs <pairs.csv>]",
+		Short: "query allows you to answer many shortest path queries with a contraction hierarchy.",
+		Long:  "query allows you to answer many shortest path queries with a contraction hierarchy saved by the contract command. Each line of the pairs file (or stdin) is a \"start,goal\" pair, and each answer is printed as a \"start,goal,cost,route\" line.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if queryFlags.hierarchy == "" {
+				cmd.Help()
+				os.Exit(1)
+			}
+
+			f, err := os.Open(queryFlags.hierarchy)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not open hierarchy file at %s: %s\n", queryFlags.hierarchy, err.Error())
+				os.Exit(1)
+			}
+
+			hierarchy, err := ch.Load(bufio.NewReader(f))
+			f.Close()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not load hierarchy from %s: %s\n", queryFlags.hierarchy, err.Error())
+				os.Exit(1)
+			}
+
+			var pairs io.Reader = os.Stdin
+			if queryFlags.pairs != "" {
+				f, err := os.Open(queryFlags.pairs)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Could not open pairs file at %s: %s\n", queryFlags.pairs, err.Error())
+					os.Exit(1)
+				}
+				defer f.Close()
+				pairs = f
+			}
+
+			reader := csv.NewReader(pairs)
+			reader.FieldsPerRecord = 2
+			reader.TrimLeadingSpace = true
+
+			writer := csv.NewWriter(os.Stdout)
+			writer.Write([]string{"start", "goal", "cost", "route"})
+
+			started := time.Now()
+			queries, settled := 0, 0
+			for {
+				pair, err := reader.Read()
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					fmt.Fprintf(os.Stderr, "Could not read pair: %s\n", err.Error())
+					os.Exit(1)
+				}
+
+				route, err := hierarchy.Query(pair[0], pair[1])
+				queries++
+				settled += route.Settled
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Could not answer query from %s to %s: %s\n", pair[0], pair[1], err.Error())
+					writer.Write([]string{pair[0], pair[1], "", ""})
+					continue
+				}
+
+				writer.Write([]string{pair[0], pair[1], search.FormatCost(route.Cost), strings.Join(route.States, " ")})
+			}
+			writer.Flush()
+
+			fmt.Fprintf(os.Stderr, "Answered %d queries in %s, settling %d states\n", queries, time.Since(started), settled)
+		},
+	}
+)
+
+func init() {
+	queryCmd.PersistentFlags().StringVar(&queryFlags.hierarchy, "hierarchy", "", "Hierarchy saved by the contract command")
+	queryCmd.PersistentFlags().StringVar(&queryFlags.pairs, "pairs", "", "CSV file of \"start,goal\" pairs to query, instead of stdin")
+}
+
+func init() {
+	rootCmd.AddCommand(queryCmd)
+}
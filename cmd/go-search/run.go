@@ -3,11 +3,13 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/porgull/go-search/pkg/environments"
 	"github.com/porgull/go-search/pkg/search"
 
 	"github.com/porgull/go-search/pkg/algorithms"
-	"github.com/porgull/go-search/pkg/environments"
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +18,15 @@ type runFlagsCfg struct {
 	load               string
 	with               string
 	customSearchParams map[string]string
+	deadline           time.Duration
+
+	checkpoint      string
+	checkpointEvery time.Duration
+	resume          string
+
+	landmarks     string
+	landmarkCount int
+	landmarkSeed  int64
 }
 
 var (
@@ -24,50 +35,33 @@ var (
 
 var (
 	runCmd = &cobra.Command{
-		Use:   "run (--on <environment>|--load <env.json>) --with <algorithm>",
+		Use:   "run (--on <environment>|--load <env.json>) --with <algorithm>[,<algorithm>...]",
 		Short: "run allows you to run and print diagnostics about the perfomance of a search algorithm.",
-		Long:  "run allows you to run and print diagnostics about the perfomance of a search algorithm.",
+		Long:  "run allows you to run and print diagnostics about the perfomance of a search algorithm. If several algorithms are listed, they're run concurrently, and the first result (or the cheapest within --deadline) is used.",
 		Run: func(cmd *cobra.Command, args []string) {
-			var env environments.Environment
 			var algo algorithms.Algorithm
 			var err error
 
-			if runFlags.on == "" && runFlags.load == "" {
-				cmd.Help()
-				os.Exit(1)
-			} else if runFlags.on != "" && runFlags.load != "" {
-				cmd.Help()
-				os.Exit(1)
-			} else if runFlags.on != "" {
-				env, err = environments.GetEnvironment(runFlags.on)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Could not get pre-made environment %s: %s\n", runFlags.on, err.Error())
-					os.Exit(1)
-				}
-			} else if runFlags.load != "" {
-				f, err := os.Open(runFlags.load)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Could not open env file at %s: %s", runFlags.load, err.Error())
-					os.Exit(1)
-				}
+			env := loadEnvironment(cmd, runFlags.on, runFlags.load, supportNegativeCosts(runFlags.with))
 
-				env, err = environments.LoadEnvironmentFrom(f)
+			if runFlags.landmarks != "" {
+				names, err := useLandmarks(env, runFlags.landmarks, runFlags.landmarkCount, runFlags.landmarkSeed)
 				if err != nil {
-					f.Close()
-					fmt.Fprintf(os.Stderr, "Could not load environment from %s: %s\n", runFlags.load, err.Error())
+					fmt.Fprintf(os.Stderr, "Could not use landmarks: %s\n", err.Error())
 					os.Exit(1)
 				}
-				f.Close()
-			}
-
-			if err = env.Validate(); err != nil {
-				fmt.Fprintf(os.Stderr, "Invalid environment: %s\n", err.Error())
-				os.Exit(1)
+				fmt.Printf("Using landmark heuristic with landmarks: %s\n", strings.Join(names, ", "))
 			}
 
 			if runFlags.with == "" {
 				cmd.Help()
 				os.Exit(1)
+			} else if strings.Contains(runFlags.with, ",") {
+				runPortfolio(env)
+				return
+			} else if runFlags.checkpoint != "" || runFlags.resume != "" {
+				runCheckpointed(env)
+				return
 			} else {
 				algo, err = algorithms.GetAlgorithm(runFlags.with)
 				if err != nil {
@@ -91,10 +85,58 @@ var (
 	}
 )
 
+// supportNegativeCosts checks if every algorithm
+// in the list supports negative costs
+func supportNegativeCosts(with string) bool {
+	for _, name := range strings.Split(with, ",") {
+		if name != "bellman_ford" {
+			return false
+		}
+	}
+	return true
+}
+
+// runPortfolio runs every algorithm in --with concurrently
+// and prints the result of the one that won
+func runPortfolio(env environments.Environment) {
+	portfolio := search.Portfolio{
+		Searchers: map[string]search.Searcher{},
+		Deadline:  runFlags.deadline,
+	}
+	for _, name := range strings.Split(runFlags.with, ",") {
+		algo, err := algorithms.GetAlgorithm(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not get algorithm %s: %s\n", name, err.Error())
+			os.Exit(1)
+		}
+		portfolio.Searchers[name] = algo
+	}
+
+	ctx := search.Context{
+		CustomSearchParams: search.CustomSearchParams(runFlags.customSearchParams),
+	}
+
+	result, err := portfolio.Run(ctx, env)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error while running algorithms %s on %s: %s\n", runFlags.with, env.Name(), err.Error())
+		os.Exit(1)
+	}
+
+	fmt.Printf("Algorithm %s won the portfolio.\n", result.Searcher)
+	result.Print()
+}
+
 func init() {
 	runCmd.PersistentFlags().StringVar(&runFlags.on, "on", "", "Use this pre-created environment to run the search algorithm")
 	runCmd.PersistentFlags().StringVar(&runFlags.load, "load", "", "Load your own environment into memory")
-	runCmd.PersistentFlags().StringVar(&runFlags.with, "with", "", "Algorithm to use to search")
+	runCmd.PersistentFlags().StringVar(&runFlags.with, "with", "", "Algorithm to use to search, or several separated by commas to run concurrently")
+	runCmd.PersistentFlags().DurationVar(&runFlags.deadline, "deadline", 0, "When running several algorithms, wait this long for the cheapest result instead of using the first")
+	runCmd.PersistentFlags().StringVar(&runFlags.checkpoint, "checkpoint", "", "Save a checkpoint of the search (a* or uniform_cost) to this file every --checkpoint-every, and when interrupted")
+	runCmd.PersistentFlags().DurationVar(&runFlags.checkpointEvery, "checkpoint-every", 10*time.Minute, "How often to save a checkpoint of the search")
+	runCmd.PersistentFlags().StringVar(&runFlags.resume, "resume", "", "Resume the search from this checkpoint file, instead of from the start")
+	runCmd.PersistentFlags().StringVar(&runFlags.landmarks, "landmarks", "", "Replace the heuristic of a state environment with landmarks, picked by \"farthest\" or \"random\", or listed as \"state1,state2\"")
+	runCmd.PersistentFlags().IntVar(&runFlags.landmarkCount, "landmark-count", 4, "Number of landmarks to pick")
+	runCmd.PersistentFlags().Int64Var(&runFlags.landmarkSeed, "landmark-seed", 1, "Seed used to pick random landmarks")
 	runCmd.PersistentFlags().StringToStringVar(&runFlags.customSearchParams, "params", map[string]string{}, "If the algorithm needs custom parameters, you can pass them here with the format \"key1=val1,key2=val2\"")
 }
 
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/mdp"
+	"github.com/porgull/go-search/pkg/search"
+	"github.com/spf13/cobra"
+)
+
+type solveFlagsCfg struct {
+	on                 string
+	load               string
+	with               string
+	customSearchParams map[string]string
+}
+
+var (
+	solveFlags = &solveFlagsCfg{}
+)
+
+var (
+	solveCmd = &cobra.Command{
+		Use:   "solve (--on <environment>|--load <env.json>) --with <solver>",
+		Short: "solve allows you to find the optimal policy of an MDP environment and print the value of every state.",
+		Long:  "solve allows you to find the optimal policy of an MDP environment and print the value of every state.",
+		Run: func(cmd *cobra.Command, args []string) {
+			env := loadEnvironment(cmd, solveFlags.on, solveFlags.load, false)
+
+			m, ok := env.(environments.MDP)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Environment %s is not an MDP\n", env.Name())
+				os.Exit(1)
+			}
+
+			if solveFlags.with == "" {
+				cmd.Help()
+				os.Exit(1)
+			}
+
+			solver, err := mdp.GetSolver(solveFlags.with)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not get solver %s: %s\n", solveFlags.with, err.Error())
+				os.Exit(1)
+			}
+
+			ctx := search.Context{
+				CustomSearchParams: search.CustomSearchParams(solveFlags.customSearchParams),
+			}
+
+			solution, err := solver.Solve(ctx, m)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error while running solver %s on %s: %s\n", solveFlags.with, env.Name(), err.Error())
+				os.Exit(1)
+			}
+
+			solution.Print()
+		},
+	}
+)
+
+func init() {
+	solveCmd.PersistentFlags().StringVar(&solveFlags.on, "on", "", "Use this pre-created environment to solve")
+	solveCmd.PersistentFlags().StringVar(&solveFlags.load, "load", "", "Load your own environment into memory")
+	solveCmd.PersistentFlags().StringVar(&solveFlags.with, "with", "", "Solver to use")
+	solveCmd.PersistentFlags().StringToStringVar(&solveFlags.customSearchParams, "params", map[string]string{}, "If the solver needs custom parameters, you can pass them here with the format \"key1=val1,key2=val2\"")
+}
+
+func init() {
+	rootCmd.AddCommand(solveCmd)
+}
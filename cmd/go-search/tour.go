@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+	"github.com/porgull/go-search/pkg/tour"
+	"github.com/spf13/cobra"
+)
+
+type tourFlagsCfg struct {
+	on         string
+	load       string
+	waypoints  []string
+	exactLimit int
+}
+
+var (
+	tourFlags = &tourFlagsCfg{}
+)
+
+var (
+	tourCmd = &cobra.Command{
+		Use:   "tour (--on <environment>|--load <env.json>) --waypoint <waypoint> [--waypoint <waypoint>...]",
+		Short: "tour allows you to find a path from the start to the goal that visits every waypoint.",
+		Long:  "tour allows you to find a path from the start to the goal that visits every waypoint, which are grid points as \"(x,y)\" or state names. The cost between every pair of waypoints is found with A*, and the order they are visited in is solved exactly for up to --exact-limit waypoints, and with 2-opt and or-opt for more.",
+		Run: func(cmd *cobra.Command, args []string) {
+			env := loadEnvironment(cmd, tourFlags.on, tourFlags.load, false)
+
+			waypointEnv, ok := env.(environments.WaypointEnvironment)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Environment %s does not support waypoints\n", env.Name())
+				os.Exit(1)
+			}
+
+			if len(tourFlags.waypoints) == 0 {
+				cmd.Help()
+				os.Exit(1)
+			}
+
+			t, err := tour.Solve(waypointEnv, tourFlags.waypoints, tourFlags.exactLimit)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not find tour: %s\n", err.Error())
+				os.Exit(1)
+			}
+
+			method := "exact"
+			if !t.Exact {
+				method = "2-opt, or-opt"
+			}
+
+			search.Result{
+				Node:        t.Node,
+				Iterations:  t.Iterations,
+				Environment: env,
+				CustomResultStats: map[string]string{
+					"order":  strings.Join(t.Order, " -> "),
+					"method": method,
+				},
+			}.Print()
+		},
+	}
+)
+
+func init() {
+	tourCmd.PersistentFlags().StringVar(&tourFlags.on, "on", "", "Use this pre-created environment to find the tour in")
+	tourCmd.PersistentFlags().StringVar(&tourFlags.load, "load", "", "Load your own environment into memory")
+	tourCmd.PersistentFlags().StringArrayVar(&tourFlags.waypoints, "waypoint", nil, "Waypoint to visit, as \"(x,y)\" on grids or the name of a state")
+	tourCmd.PersistentFlags().IntVar(&tourFlags.exactLimit, "exact-limit", tour.DefaultExactLimit, "Most waypoints to solve the order of exactly")
+}
+
+func init() {
+	rootCmd.AddCommand(tourCmd)
+}
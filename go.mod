@@ -1,6 +1,6 @@
 module github.com/porgull/go-search
 
-go 1.14
+go 1.18
 
 require (
 	github.com/fsnotify/fsnotify v1.4.9 // indirect
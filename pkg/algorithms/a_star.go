@@ -10,81 +10,153 @@ import (
 
 // AStar implements the A* search algorithm. See
 // https://en.wikipedia.org/wiki/A*_search_algorithm
-// for a quick overview.
+// for a quick overview. It can take the `tie_break`
+// and `tie_break_seed` custom arguments (see TieBreak),
+// can be stepped through (see Stepper) and resumed
+// from a checkpoint (see Checkpointer)
 type AStar struct {
-	queue *PriorityNodeQueue
+	stepState
 
-	cost              map[string]int
-	costWithHeuristic map[string]int
+	queue *PriorityNodeQueue
 
-	iterations int
+	cost              map[environments.NodeKey]float64
+	costWithHeuristic map[environments.NodeKey]float64
 }
 
 // Run runs A* on the environment and returns the result
 func (a AStar) Run(ctx search.Context, e environments.Environment) (search.Result, error) {
-	a.setStart(e.Start())
-
-	node, err := a.findGoal(e)
-	if err != nil {
+	if err := a.Start(ctx, e); err != nil {
 		return search.Result{}, err
 	}
 
-	return search.Result{
-		Node:        node,
-		Iterations:  a.iterations,
-		Environment: e,
-	}, nil
+	// only steppers are asked for the expanded nodes
+	a.forgetClosed()
+	return runSteps(&a)
+}
+
+// Start starts a new search of the environment
+func (a *AStar) Start(ctx search.Context, e environments.Environment) error {
+	config, err := tieBreakConfig(ctx.CustomSearchParams, PriorityNodeQueueConfig{})
+	if err != nil {
+		return err
+	}
+	a.stepState.start(ctx, e)
+	a.setStart(e.Start(), config)
+	return nil
 }
 
 // initialize AStar's fields for this environment
-func (a *AStar) setStart(start environments.Node) {
-	a.cost = make(map[string]int, 512)
-	a.cost[start.Name()] = 0
+func (a *AStar) setStart(start environments.Node, config PriorityNodeQueueConfig) {
+	a.cost = make(map[environments.NodeKey]float64, 512)
+	a.cost[environments.KeyOf(start)] = 0
+
+	a.costWithHeuristic = make(map[environments.NodeKey]float64, 512)
+	a.costWithHeuristic[environments.KeyOf(start)] = environments.HeuristicOf(start)
 
-	a.costWithHeuristic = make(map[string]int, 512)
-	a.costWithHeuristic[start.Name()] = start.Heuristic()
+	config.CostMap = a.cost
+	a.queue = NewPriorityNodeQueue(start, a.costWithHeuristic, config)
+}
 
-	a.iterations = 0
+// Frontier returns the nodes waiting to be
+// expanded, in the order they'll be expanded
+func (a *AStar) Frontier() []environments.Node {
+	return a.queue.Ordered()
+}
 
-	a.queue = NewPriorityNodeQueue(start, a.costWithHeuristic, PriorityNodeQueueConfig{})
+// Checkpoint returns the state of the search so far
+func (a *AStar) Checkpoint() (*Checkpoint, error) {
+	return newCheckpoint("a*", &a.stepState, a.queue, a.cost)
 }
 
-// find and return the goal node
-func (a *AStar) findGoal(e environments.Environment) (environments.Node, error) {
-	// if nothing in queue/frontier, then it is impossible
-	// to find the goal node
-	for a.queue.Len() > 0 {
-		a.iterations++
-		currentNode := heap.Pop(a.queue).(environments.Node)
+// Resume continues the search of the environment from the checkpoint
+func (a *AStar) Resume(ctx search.Context, e environments.Environment, c *Checkpoint) error {
+	config, err := tieBreakConfig(ctx.CustomSearchParams, PriorityNodeQueueConfig{})
+	if err != nil {
+		return err
+	}
 
-		if e.IsGoalNode(currentNode) {
-			return currentNode, nil
-		}
+	frontier, err := c.restore("a*", e)
+	if err != nil {
+		return err
+	}
 
-		currentNodeCost := a.cost[currentNode.Name()]
-		for _, child := range currentNode.Children() {
-			childCost := currentNodeCost + child.Cost()
-
-			previousChildCost, seen := a.cost[child.Name()]
-
-			// if we found a better route to this node OR this
-			// node hasn't been seen yet
-			if (!seen) || (seen && previousChildCost > childCost) {
-				a.cost[child.Name()] = childCost
-				a.costWithHeuristic[child.Name()] = childCost + child.Heuristic()
-				if currIdx, inQueue := a.queue.NodeIndexes[child.Name()]; inQueue {
-					// if the child is already in the frontier, replace it
-					// with this node b/c this node has a lower cost
-					a.queue.Frontier[currIdx] = child
-					// now that the cost has changed,
-					// fix the placement of that node
-					heap.Fix(a.queue, currIdx)
-				} else {
-					heap.Push(a.queue, child)
-				}
+	a.stepState.start(ctx, e)
+	a.iterations = c.Iterations
+
+	a.cost = make(map[environments.NodeKey]float64, len(c.Costs))
+	for key, cost := range c.Costs {
+		a.cost[key] = cost
+	}
+
+	a.costWithHeuristic = make(map[environments.NodeKey]float64, len(c.Costs))
+	for _, node := range frontier {
+		a.costWithHeuristic[environments.KeyOf(node)] = a.cost[environments.KeyOf(node)] + environments.HeuristicOf(node)
+	}
+
+	config.CostMap = a.cost
+	a.queue = restoreQueue(frontier, a.costWithHeuristic, config)
+	return nil
+}
+
+// Step expands the node with the lowest cost
+// and heuristic, and returns what happened
+func (a *AStar) Step() (StepEvent, bool) {
+	if a.done {
+		return StepEvent{}, true
+	}
+	if a.ctx.Cancelled() {
+		a.stop(nil, search.ErrCancelled)
+		return StepEvent{}, true
+	}
+
+	currentNode := heap.Pop(a.queue).(environments.Node)
+	event := a.expand(currentNode)
+
+	if a.env.IsGoalNode(currentNode) {
+		event.Goal = true
+		a.stop(currentNode, nil)
+		return event, true
+	}
+
+	currentNodeCost := a.cost[environments.KeyOf(currentNode)]
+	// the added children are kept in the slice of
+	// children, which isn't needed once they're added
+	children := currentNode.Children()
+	event.Added = children[:0]
+	for _, child := range children {
+		if err := environments.CheckCost(currentNode, child); err != nil {
+			a.stop(nil, err)
+			return event, true
+		}
+		childKey := environments.KeyOf(child)
+		childCost := currentNodeCost + environments.CostOf(child)
+
+		previousChildCost, seen := a.cost[childKey]
+
+		// if we found a better route to this node OR this
+		// node hasn't been seen yet
+		if (!seen) || (seen && previousChildCost > childCost) {
+			a.cost[childKey] = childCost
+			a.costWithHeuristic[childKey] = childCost + environments.HeuristicOf(child)
+			if currIdx, inQueue := a.queue.NodeIndexes[childKey]; inQueue {
+				// if the child is already in the frontier, replace it
+				// with this node b/c this node has a lower cost
+				a.queue.Frontier[currIdx] = child
+				// now that the cost has changed,
+				// fix the placement of that node
+				heap.Fix(a.queue, currIdx)
+			} else {
+				heap.Push(a.queue, child)
 			}
+			event.Added = append(event.Added, child)
 		}
+	}
 
+	// if nothing in queue/frontier, then it is impossible
+	// to find the goal node
+	if a.queue.Len() == 0 {
+		a.stop(nil, fmt.Errorf("frontier is empty; searched entire space, but could not find goal state"))
+		return event, true
 	}
-	return nil, fmt.Errorf("frontier is empty; searched entire space, but could not find goal state")
+	return event, false
 }
@@ -0,0 +1,118 @@
+package algorithms
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+// AndOrSearch implements AND-OR graph search for environments
+// where steps can have several outcomes (see
+// environments.ChanceNode). It finds a conditional plan that
+// reaches the goal no matter which outcomes occur, trying
+// the children with the lowest cost and heuristic first
+type AndOrSearch struct {
+	// path contains the names of the nodes
+	// on the path currently being searched
+	path map[environments.NodeKey]bool
+
+	// ctx is checked for cancellation before expanding nodes
+	ctx search.Context
+
+	iterations int
+}
+
+// Run runs AND-OR search on the environment and returns the plan
+func (a AndOrSearch) Run(ctx search.Context, e environments.Environment) (search.Result, error) {
+	a.path = make(map[environments.NodeKey]bool, 64)
+	a.ctx = ctx
+	a.iterations = 0
+
+	start := e.Start()
+	plan := a.orSearch(e, start)
+	if ctx.Cancelled() {
+		return search.Result{}, search.ErrCancelled
+	}
+	if plan == nil {
+		return search.Result{
+			Iterations:  a.iterations,
+			Environment: e,
+		}, fmt.Errorf("explored entire search space, but could not find a plan that always reaches the goal")
+	}
+
+	return search.Result{
+		Plan:        plan,
+		Iterations:  a.iterations,
+		Environment: e,
+	}, nil
+}
+
+// orSearch returns a plan from the node through any one
+// of its children, or nil if none of them have a plan
+// or the search was cancelled
+func (a *AndOrSearch) orSearch(e environments.Environment, node environments.Node) *search.Plan {
+	if a.ctx.Cancelled() {
+		return nil
+	}
+	a.iterations++
+
+	if e.IsGoalNode(node) {
+		return &search.Plan{Node: node}
+	}
+
+	// if this node is already on the path, then
+	// going further would only loop back here
+	if a.path[environments.KeyOf(node)] {
+		return nil
+	}
+	a.path[environments.KeyOf(node)] = true
+	defer delete(a.path, environments.KeyOf(node))
+
+	children := node.Children()
+	sort.SliceStable(children, func(i, j int) bool {
+		return environments.CostOf(children[i])+environments.HeuristicOf(children[i]) < environments.CostOf(children[j])+environments.HeuristicOf(children[j])
+	})
+
+	for _, child := range children {
+		if outcomes := a.andSearch(e, outcomesOf(child)); outcomes != nil {
+			return &search.Plan{
+				Node:     node,
+				Step:     child,
+				Outcomes: outcomes,
+			}
+		}
+	}
+	return nil
+}
+
+// andSearch returns a plan for every one of the nodes,
+// or nil if any of them doesn't have a plan
+func (a *AndOrSearch) andSearch(e environments.Environment, nodes []environments.Node) []*search.Plan {
+	plans := make([]*search.Plan, len(nodes))
+	for i, node := range nodes {
+		plans[i] = a.orSearch(e, node)
+		if plans[i] == nil {
+			return nil
+		}
+	}
+	return plans
+}
+
+// outcomesOf returns the possible outcomes of the node,
+// which is just the node itself if it is deterministic
+func outcomesOf(node environments.Node) []environments.Node {
+	if chanceNode, ok := node.(environments.ChanceNode); ok {
+		if outcomes := chanceNode.Outcomes(); len(outcomes) > 0 {
+			out := make([]environments.Node, 0, len(outcomes))
+			for _, outcome := range outcomes {
+				if outcome.Probability > 0 {
+					out = append(out, outcome.Node)
+				}
+			}
+			return out
+		}
+	}
+	return []environments.Node{node}
+}
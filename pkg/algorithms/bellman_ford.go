@@ -0,0 +1,148 @@
+package algorithms
+
+import (
+	"container/list"
+	"fmt"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+	"github.com/porgull/go-search/pkg/shortestpath"
+)
+
+// BellmanFord implements a label correcting version of the
+// Bellman-Ford algorithm, which finds the cheapest path to the
+// goal even when some costs are negative. Rather than relaxing
+// every step once per state, it queues the nodes whose cost
+// dropped, but it still has to search everything reachable from
+// the start, as a cheaper path to the goal may be found at any
+// point. If a cycle with a negative total cost can be reached,
+// it returns a *shortestpath.NegativeCycleError containing it. See
+// https://en.wikipedia.org/wiki/Bellman%E2%80%93Ford_algorithm
+type BellmanFord struct {
+	// queue contains the keys of the nodes whose
+	// children haven't been relaxed since their
+	// cost last dropped, and best is the node of
+	// each key on its cheapest path so far
+	queue  *list.List
+	queued map[environments.NodeKey]bool
+	best   map[environments.NodeKey]environments.Node
+	cost   map[environments.NodeKey]float64
+	steps  map[environments.NodeKey]int
+	goals  []environments.NodeKey
+	isGoal map[environments.NodeKey]bool
+
+	iterations int
+}
+
+// Run runs Bellman-Ford on the environment and returns the result
+func (a BellmanFord) Run(ctx search.Context, e environments.Environment) (search.Result, error) {
+	a.setStart(e.Start())
+
+	node, err := a.findGoal(ctx, e)
+	if err != nil {
+		return search.Result{
+			Iterations:  a.iterations,
+			Environment: e,
+		}, err
+	}
+
+	return search.Result{
+		Node:        node,
+		Iterations:  a.iterations,
+		Environment: e,
+	}, nil
+}
+
+// initialize the fields for this environment
+func (a *BellmanFord) setStart(start environments.Node) {
+	startKey := environments.KeyOf(start)
+
+	a.queue = list.New()
+	a.queue.PushBack(startKey)
+	a.queued = map[environments.NodeKey]bool{startKey: true}
+
+	a.best = map[environments.NodeKey]environments.Node{startKey: start}
+	a.cost = map[environments.NodeKey]float64{startKey: 0}
+	a.steps = map[environments.NodeKey]int{startKey: 0}
+
+	a.goals = nil
+	a.isGoal = map[environments.NodeKey]bool{}
+
+	a.iterations = 0
+}
+
+// find and return the cheapest goal node
+func (a *BellmanFord) findGoal(ctx search.Context, e environments.Environment) (environments.Node, error) {
+	for a.queue.Len() > 0 {
+		if ctx.Cancelled() {
+			return nil, search.ErrCancelled
+		}
+		a.iterations++
+
+		currentKey := a.queue.Remove(a.queue.Front()).(environments.NodeKey)
+		delete(a.queued, currentKey)
+
+		currentNode := a.best[currentKey]
+		if e.IsGoalNode(currentNode) && !a.isGoal[currentKey] {
+			a.isGoal[currentKey] = true
+			a.goals = append(a.goals, currentKey)
+		}
+
+		for _, child := range currentNode.Children() {
+			childKey := environments.KeyOf(child)
+			childCost := a.cost[currentKey] + environments.CostOf(child)
+			if previousChildCost, seen := a.cost[childKey]; seen && previousChildCost <= childCost {
+				continue
+			}
+
+			a.best[childKey] = child
+			a.cost[childKey] = childCost
+			a.steps[childKey] = a.steps[currentKey] + 1
+
+			// a path with at least as many steps as there are
+			// nodes goes through one twice, and it only became
+			// the cheapest by going around a negative cycle
+			if a.steps[childKey] >= len(a.cost) {
+				return nil, &shortestpath.NegativeCycleError{Cycle: negativeCycle(child)}
+			}
+
+			if !a.queued[childKey] {
+				a.queued[childKey] = true
+				a.queue.PushBack(childKey)
+			}
+		}
+	}
+
+	var goal environments.Node
+	for _, goalKey := range a.goals {
+		if goal == nil || a.cost[goalKey] < a.cost[environments.KeyOf(goal)] {
+			goal = a.best[goalKey]
+		}
+	}
+	if goal == nil {
+		return nil, fmt.Errorf("queue is empty; searched entire space, but could not find goal state")
+	}
+	return goal, nil
+}
+
+// negativeCycle returns the names of the nodes on the first
+// cycle in the node's path, in the order they're stepped through
+func negativeCycle(node environments.Node) []string {
+	var path []environments.Node
+	index := map[environments.NodeKey]int{}
+	for ; node != nil; node = node.Parent() {
+		key := environments.KeyOf(node)
+		if i, seen := index[key]; seen {
+			path = path[i:]
+			break
+		}
+		index[key] = len(path)
+		path = append(path, node)
+	}
+
+	cycle := make([]string, len(path))
+	for i, cycleNode := range path {
+		cycle[len(path)-1-i] = cycleNode.Name()
+	}
+	return cycle
+}
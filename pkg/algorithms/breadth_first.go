@@ -1,7 +1,6 @@
 package algorithms
 
 import (
-	"container/heap"
 	"fmt"
 
 	"github.com/porgull/go-search/pkg/environments"
@@ -9,77 +8,110 @@ import (
 )
 
 // BreadthFirst implements the breadth first
-// search algorithm
+// search algorithm, and can be stepped
+// through (see Stepper). Its open and closed
+// lists can be stored on disk with the
+// `storage` custom argument (see newLists)
 type BreadthFirst struct {
-	queue *PriorityNodeQueue
-
-	depth map[string]int
-
-	iterations int
+	stepState
+	lists
 }
 
-// Run runs A* on the environment and returns the result
+// Run runs breadth first search on the environment and returns the result
 func (a BreadthFirst) Run(ctx search.Context, e environments.Environment) (search.Result, error) {
-	a.setStart(e.Start())
-
-	node, err := a.findGoal(e)
-	if err != nil {
+	if err := a.Start(ctx, e); err != nil {
 		return search.Result{}, err
 	}
 
-	return search.Result{
-		Node:        node,
-		Iterations:  a.iterations,
-		Environment: e,
-	}, nil
+	// only steppers are asked for the expanded nodes
+	a.forgetClosed()
+	return runSteps(&a)
 }
 
-// initialize fields for this environment
-func (a *BreadthFirst) setStart(start environments.Node) {
-	a.depth = make(map[string]int, 512)
-	a.depth[start.Name()] = 1
-
-	a.iterations = 0
-
-	a.queue = NewPriorityNodeQueue(start, a.depth, PriorityNodeQueueConfig{})
+// Start starts a new search of the environment
+func (a *BreadthFirst) Start(ctx search.Context, e environments.Environment) error {
+	lists, err := newLists(ctx, e, e.Start(), 1, PriorityNodeQueueConfig{})
+	if err != nil {
+		return err
+	}
+	a.stepState.start(ctx, e)
+	a.lists = lists
+	if a.dir != "" {
+		a.forgetClosed()
+	}
+	return nil
 }
 
-// find and return the goal node
-func (a *BreadthFirst) findGoal(e environments.Environment) (environments.Node, error) {
-	// if nothing in queue/frontier, then it is impossible
-	// to find the goal node
-	for a.queue.Len() > 0 {
-		a.iterations++
-		currentNode := heap.Pop(a.queue).(environments.Node)
+// Frontier returns the nodes waiting to be expanded, in
+// the order they'll be expanded, or nil if they're stored
+// on disk and can't be read
+func (a *BreadthFirst) Frontier() []environments.Node {
+	nodes, err := a.openList.Nodes()
+	if err != nil {
+		return nil
+	}
+	return nodes
+}
 
-		if e.IsGoalNode(currentNode) {
-			return currentNode, nil
-		}
+// finish ends the search, and closes the lists
+func (a *BreadthFirst) finish(goal environments.Node, err error) {
+	if closeErr := a.lists.close(); err == nil && closeErr != nil {
+		goal, err = nil, closeErr
+	}
+	a.stop(goal, err)
+}
 
-		for _, child := range currentNode.Children() {
-			childIdx, inQueue := a.queue.NodeIndexes[child.Name()]
+// Step expands the shallowest node, and returns what happened
+func (a *BreadthFirst) Step() (StepEvent, bool) {
+	if a.done {
+		return StepEvent{}, true
+	}
+	if a.ctx.Cancelled() {
+		a.finish(nil, search.ErrCancelled)
+		return StepEvent{}, true
+	}
 
-			prevDepth, seenPrev := a.depth[child.Name()]
-			currDepth := a.depth[currentNode.Name()] + 1
+	currentNode, currentDepth, err := a.pop()
+	if err != nil {
+		a.finish(nil, err)
+		return StepEvent{}, true
+	} else if currentNode == nil {
+		a.finish(nil, fmt.Errorf("frontier is empty; searched entire space, but could not find goal state"))
+		return StepEvent{}, true
+	}
+	event := a.expand(currentNode)
 
-			if seenPrev && prevDepth < currDepth {
-				continue
-			}
+	if a.env.IsGoalNode(currentNode) {
+		event.Goal = true
+		a.finish(a.closedList.Path(currentNode))
+		return event, true
+	}
 
-			if !inQueue {
-				a.depth[child.Name()] = currDepth
-				// new node, just add it
-				heap.Push(a.queue, child)
-			} else {
-				a.depth[child.Name()] = currDepth
-				// we found a new route to the node. let's
-				// update the depth and fix its placement in the
-				// queue
-				a.queue.Frontier[childIdx] = child
-				heap.Fix(a.queue, childIdx)
-			}
+	// the added children are kept in the slice of
+	// children, which isn't needed once they're added
+	children := currentNode.Children()
+	event.Added = children[:0]
+	for _, child := range children {
+		// a child reached at a shallower depth before is
+		// skipped, and otherwise added or moved up the list
+		reached, err := a.closedList.Reach(child, currentDepth+1)
+		if err == nil && reached {
+			err = a.openList.Push(child, currentDepth+1)
+		}
+		if err != nil {
+			a.finish(nil, err)
+			return event, true
+		}
+		if reached {
+			event.Added = append(event.Added, child)
 		}
+	}
 
+	// if nothing in queue/frontier, then it is impossible
+	// to find the goal node
+	if a.openList.Len() == 0 {
+		a.finish(nil, fmt.Errorf("frontier is empty; searched entire space, but could not find goal state"))
+		return event, true
 	}
-	return nil, fmt.Errorf("frontier is empty; searched entire space, but could not find goal state")
+	return event, false
 }
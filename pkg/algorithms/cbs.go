@@ -0,0 +1,373 @@
+package algorithms
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+const defaultCBSMaxNodes = 10000
+
+// ConflictBasedSearch implements Conflict-Based Search for multi-agent
+// grids (see environments.MultiAgentGridEnvironment), which finds
+// paths for every agent with the lowest sum of costs. Each agent is
+// planned for on its own with A* through space and time, and when
+// two agents' paths conflict (they're on the same point at the same
+// time, or swap points), the search branches on which of them is
+// constrained to avoid it. It can take the `max_nodes` custom
+// argument, which limits how many branches are expanded. See
+// https://doi.org/10.1016/j.artint.2014.11.006
+type ConflictBasedSearch struct {
+	env      *environments.MultiAgentGridEnvironment
+	maxNodes int
+
+	iterations         int
+	lowLevelExpansions int
+}
+
+// Run runs CBS on the environment and returns the
+// node of every agent following its path
+func (a ConflictBasedSearch) Run(ctx search.Context, e environments.Environment) (search.Result, error) {
+	env, ok := e.(*environments.MultiAgentGridEnvironment)
+	if !ok {
+		return search.Result{}, fmt.Errorf("environment %s is not a multi-agent grid", e.Name())
+	}
+	a.env = env
+
+	var err error
+	if a.maxNodes, err = intParam(ctx.CustomSearchParams, "max_nodes", defaultCBSMaxNodes); err != nil {
+		return search.Result{}, err
+	}
+
+	solution, err := a.findSolution(ctx)
+	if err != nil {
+		return search.Result{
+			Iterations:  a.iterations,
+			Environment: e,
+		}, err
+	}
+
+	node, err := env.PathsNode(solution.paths)
+	if err != nil {
+		return search.Result{}, err
+	}
+
+	makespan := 0
+	for _, path := range solution.paths {
+		if len(path)-1 > makespan {
+			makespan = len(path) - 1
+		}
+	}
+
+	return search.Result{
+		Node:        node,
+		Iterations:  a.iterations,
+		Environment: e,
+		CustomResultStats: map[string]string{
+			"sum_of_costs":         strconv.Itoa(solution.cost),
+			"makespan":             strconv.Itoa(makespan),
+			"low_level_expansions": strconv.Itoa(a.lowLevelExpansions),
+		},
+	}, nil
+}
+
+// constraint forbids an agent from being on a point at
+// a time, or if edge is set, from moving from a point
+// to another to arrive at a time
+type constraint struct {
+	agent    int
+	edge     bool
+	from, to environments.Vector2D
+	time     int
+}
+
+// conflict is two agents on the same point at the same
+// time, or, if edge is set, swapping points
+type conflict struct {
+	agents   [2]int
+	edge     bool
+	from, to environments.Vector2D
+	time     int
+}
+
+// constraintNode is a node of the constraint tree,
+// with the paths that satisfy its constraints
+type constraintNode struct {
+	constraints []constraint
+	paths       [][]environments.Vector2D
+	cost        int
+	conflicts   int
+}
+
+// findSolution expands the constraint tree with
+// the lowest cost first, until a node's paths
+// don't conflict
+func (a *ConflictBasedSearch) findSolution(ctx search.Context) (*constraintNode, error) {
+	a.iterations = 0
+	a.lowLevelExpansions = 0
+
+	root := &constraintNode{
+		paths: make([][]environments.Vector2D, len(a.env.Agents)),
+	}
+	for agent := range a.env.Agents {
+		root.paths[agent] = a.findPath(agent, nil)
+		if root.paths[agent] == nil {
+			return nil, fmt.Errorf("agent %s cannot reach its goal", a.env.Agents[agent].Name)
+		}
+	}
+	a.evaluate(root)
+
+	queue := &constraintQueue{root}
+	for queue.Len() > 0 {
+		if ctx.Cancelled() {
+			return nil, search.ErrCancelled
+		}
+		if a.iterations >= a.maxNodes {
+			return nil, fmt.Errorf("did not find paths without conflicts within %d nodes", a.maxNodes)
+		}
+		a.iterations++
+
+		current := heap.Pop(queue).(*constraintNode)
+		c, found := firstConflict(current.paths)
+		if !found {
+			return current, nil
+		}
+
+		for i, agent := range c.agents {
+			added := constraint{agent: agent, edge: c.edge, from: c.from, to: c.to, time: c.time}
+			if i == 1 && c.edge {
+				// the second agent moves the other way
+				added.from, added.to = c.to, c.from
+			}
+
+			child := &constraintNode{
+				constraints: append(append([]constraint(nil), current.constraints...), added),
+				paths:       append([][]environments.Vector2D(nil), current.paths...),
+			}
+
+			child.paths[agent] = a.findPath(agent, child.constraints)
+			if child.paths[agent] == nil {
+				continue
+			}
+			a.evaluate(child)
+			heap.Push(queue, child)
+		}
+	}
+	return nil, fmt.Errorf("explored every constraint, but could not find paths without conflicts")
+}
+
+// evaluate sets the sum of costs and
+// the number of conflicts of the node
+func (a *ConflictBasedSearch) evaluate(node *constraintNode) {
+	node.cost = 0
+	for _, path := range node.paths {
+		node.cost += len(path) - 1
+	}
+	node.conflicts = countConflicts(node.paths)
+}
+
+// timedPoint is a point at a time
+type timedPoint struct {
+	point environments.Vector2D
+	time  int
+}
+
+// findPath runs A* through space and time for the agent,
+// avoiding its constraints, and returns the point it is on
+// at every time until it reaches its goal and can stay there,
+// or nil if there's no such path
+func (a *ConflictBasedSearch) findPath(agent int, constraints []constraint) []environments.Vector2D {
+	start, goal := a.env.AgentStart(agent), a.env.AgentGoal(agent)
+
+	vertices := map[timedPoint]bool{}
+	edges := map[[2]timedPoint]bool{}
+
+	// the agent can only stay at its goal
+	// after it's last constrained there
+	lastGoalConstraint := -1
+	latest := 0
+	for _, c := range constraints {
+		if c.agent != agent {
+			continue
+		}
+
+		if c.edge {
+			edges[[2]timedPoint{{c.from, c.time - 1}, {c.to, c.time}}] = true
+		} else {
+			vertices[timedPoint{c.from, c.time}] = true
+			if c.from.Equals(goal) && c.time > lastGoalConstraint {
+				lastGoalConstraint = c.time
+			}
+		}
+
+		if c.time > latest {
+			latest = c.time
+		}
+	}
+
+	// after the latest constraint, waiting never helps, so
+	// any path longer than visiting every point is pointless
+	size := a.env.Size()
+	maxTime := latest + size.X()*size.Y()
+
+	parents := map[timedPoint]timedPoint{}
+	queue := &timedPointQueue{{timedPoint: timedPoint{start, 0}, priority: start.ManhattanDistanceTo(goal)}}
+	seen := map[timedPoint]bool{{start, 0}: true}
+	for queue.Len() > 0 {
+		a.lowLevelExpansions++
+		current := heap.Pop(queue).(queuedTimedPoint).timedPoint
+
+		if current.point.Equals(goal) && current.time > lastGoalConstraint {
+			path := make([]environments.Vector2D, current.time+1)
+			for t := current; ; t = parents[t] {
+				path[t.time] = t.point
+				if t.time == 0 {
+					break
+				}
+			}
+			return path
+		}
+
+		if current.time >= maxTime {
+			continue
+		}
+
+		// the moves are pushed in the order of their
+		// actions, so ties are always popped the same way
+		moves := a.env.Moves(current.point)
+		actions := make([]string, 0, len(moves))
+		for action := range moves {
+			actions = append(actions, action)
+		}
+		sort.Strings(actions)
+
+		for _, action := range actions {
+			next := moves[action]
+			child := timedPoint{next, current.time + 1}
+			if seen[child] || vertices[child] || edges[[2]timedPoint{current, child}] {
+				continue
+			}
+
+			seen[child] = true
+			parents[child] = current
+			heap.Push(queue, queuedTimedPoint{
+				timedPoint: child,
+				priority:   child.time + next.ManhattanDistanceTo(goal),
+			})
+		}
+	}
+	return nil
+}
+
+// positionAt returns the point on the path at the time,
+// which is the end of the path once it has finished
+func positionAt(path []environments.Vector2D, time int) environments.Vector2D {
+	if time >= len(path) {
+		return path[len(path)-1]
+	}
+	return path[time]
+}
+
+// conflicts calls found for every conflict between the
+// paths, in order of time, until found returns false
+func conflicts(paths [][]environments.Vector2D, found func(c conflict) bool) {
+	length := 0
+	for _, path := range paths {
+		if len(path) > length {
+			length = len(path)
+		}
+	}
+
+	for t := 1; t < length; t++ {
+		for i := range paths {
+			for j := i + 1; j < len(paths); j++ {
+				pi, pj := positionAt(paths[i], t), positionAt(paths[j], t)
+				if pi.Equals(pj) {
+					if !found(conflict{agents: [2]int{i, j}, from: pi, time: t}) {
+						return
+					}
+					continue
+				}
+
+				previousI, previousJ := positionAt(paths[i], t-1), positionAt(paths[j], t-1)
+				if pi.Equals(previousJ) && pj.Equals(previousI) {
+					if !found(conflict{agents: [2]int{i, j}, edge: true, from: previousI, to: pi, time: t}) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// firstConflict returns the earliest conflict between the paths
+func firstConflict(paths [][]environments.Vector2D) (conflict, bool) {
+	var first conflict
+	found := false
+	conflicts(paths, func(c conflict) bool {
+		first, found = c, true
+		return false
+	})
+	return first, found
+}
+
+// countConflicts returns the number of conflicts between the paths
+func countConflicts(paths [][]environments.Vector2D) int {
+	count := 0
+	conflicts(paths, func(conflict) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// constraintQueue implements heap.Interface, popping the node
+// with the lowest cost, and then the fewest conflicts
+type constraintQueue []*constraintNode
+
+func (q constraintQueue) Len() int { return len(q) }
+func (q constraintQueue) Less(i, j int) bool {
+	if q[i].cost != q[j].cost {
+		return q[i].cost < q[j].cost
+	}
+	return q[i].conflicts < q[j].conflicts
+}
+func (q constraintQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *constraintQueue) Push(x interface{}) { *q = append(*q, x.(*constraintNode)) }
+func (q *constraintQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	popped := old[n-1]
+	*q = old[:n-1]
+	return popped
+}
+
+type queuedTimedPoint struct {
+	timedPoint
+	priority int
+}
+
+// timedPointQueue implements heap.Interface, popping the point
+// with the lowest priority, and then the latest time
+type timedPointQueue []queuedTimedPoint
+
+func (q timedPointQueue) Len() int { return len(q) }
+func (q timedPointQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority < q[j].priority
+	}
+	return q[i].time > q[j].time
+}
+func (q timedPointQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *timedPointQueue) Push(x interface{}) { *q = append(*q, x.(queuedTimedPoint)) }
+func (q *timedPointQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	popped := old[n-1]
+	*q = old[:n-1]
+	return popped
+}
@@ -0,0 +1,205 @@
+package algorithms
+
+import (
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+// Checkpointer is implemented by the steppers whose search can
+// be saved part way through, and resumed later (e.g. after the
+// machine running it restarts)
+type Checkpointer interface {
+	Stepper
+
+	// Checkpoint returns the state of the search
+	// so far, which can't have ended yet
+	Checkpoint() (*Checkpoint, error)
+
+	// Resume continues the search of the environment from
+	// the checkpoint, instead of starting a new one. Closed
+	// only contains the nodes expanded since resuming
+	Resume(ctx search.Context, e environments.Environment, c *Checkpoint) error
+}
+
+// Checkpoint is the state of a search part way through. Nodes
+// can't be saved, so the path to every node on the frontier is
+// saved instead, and found again from the start when resuming
+type Checkpoint struct {
+	// Algorithm and Environment are the names of what was
+	// searched, which have to match when resuming
+	Algorithm   string `json:"algorithm"`
+	Environment string `json:"environment"`
+
+	Iterations int `json:"iterations"`
+
+	// Costs contains the lowest cost found so far
+	// of every node that was reached
+	Costs map[environments.NodeKey]float64 `json:"costs"`
+
+	// Nodes contains the nodes on the paths to the frontier,
+	// each after its parent, and Frontier contains the
+	// indexes of the nodes that are on the frontier
+	Nodes    []CheckpointNode `json:"nodes"`
+	Frontier []int            `json:"frontier"`
+}
+
+// CheckpointNode is a node on the path to the frontier
+type CheckpointNode struct {
+	Key environments.NodeKey `json:"key"`
+
+	// Parent is the index of the node's
+	// parent, or -1 for the start node
+	Parent int `json:"parent"`
+}
+
+// LoadCheckpoint reads a checkpoint saved with Save
+func LoadCheckpoint(r io.Reader) (*Checkpoint, error) {
+	c := &Checkpoint{}
+	if err := json.NewDecoder(r).Decode(c); err != nil {
+		return nil, fmt.Errorf("could not decode checkpoint: %w", err)
+	}
+
+	for i, node := range c.Nodes {
+		if node.Parent < -1 || node.Parent >= i {
+			return nil, fmt.Errorf("node %d of checkpoint has parent %d, which isn't before it", i, node.Parent)
+		}
+	}
+	for _, index := range c.Frontier {
+		if index < 0 || index >= len(c.Nodes) {
+			return nil, fmt.Errorf("frontier of checkpoint refers to missing node %d", index)
+		}
+	}
+
+	return c, nil
+}
+
+// Save writes the checkpoint so it can be loaded later
+func (c *Checkpoint) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(c)
+}
+
+// newCheckpoint returns the checkpoint of a search
+// with the frontier and costs
+func newCheckpoint(algorithm string, s *stepState, queue *PriorityNodeQueue, costs map[environments.NodeKey]float64) (*Checkpoint, error) {
+	if s.done {
+		return nil, fmt.Errorf("search has already ended")
+	}
+
+	c := &Checkpoint{
+		Algorithm:   algorithm,
+		Environment: s.env.Name(),
+		Iterations:  s.iterations,
+		Costs:       make(map[environments.NodeKey]float64, len(costs)),
+		Frontier:    make([]int, len(queue.Frontier)),
+	}
+	for key, cost := range costs {
+		c.Costs[key] = cost
+	}
+
+	// nodes with the same key and parent are the same, so
+	// paths through the same nodes only save them once
+	type savedNode struct {
+		key    environments.NodeKey
+		parent int
+	}
+	indexes := map[savedNode]int{}
+
+	// nodes are also remembered by identity (if their type is
+	// comparable, like pointers are), so each path is only
+	// followed up to the first node that was already saved
+	saved := map[environments.Node]int{}
+
+	for i, node := range queue.Frontier {
+		var path []environments.Node
+		parent := -1
+		for ; node != nil; node = node.Parent() {
+			if reflect.TypeOf(node).Comparable() {
+				if index, ok := saved[node]; ok {
+					parent = index
+					break
+				}
+			}
+			path = append(path, node)
+		}
+
+		for j := len(path) - 1; j >= 0; j-- {
+			key := savedNode{key: environments.KeyOf(path[j]), parent: parent}
+			index, ok := indexes[key]
+			if !ok {
+				index = len(c.Nodes)
+				indexes[key] = index
+				c.Nodes = append(c.Nodes, CheckpointNode{Key: key.key, Parent: parent})
+			}
+			if reflect.TypeOf(path[j]).Comparable() {
+				saved[path[j]] = index
+			}
+			parent = index
+		}
+		c.Frontier[i] = parent
+	}
+	return c, nil
+}
+
+// restore checks that the checkpoint is of the algorithm on the
+// environment, and finds the nodes on its frontier again by
+// following their paths from the start of the environment
+func (c *Checkpoint) restore(algorithm string, e environments.Environment) ([]environments.Node, error) {
+	if c.Algorithm != algorithm {
+		return nil, fmt.Errorf("checkpoint is of %s, not %s", c.Algorithm, algorithm)
+	}
+	if c.Environment != e.Name() {
+		return nil, fmt.Errorf("checkpoint is of environment %s, not %s", c.Environment, e.Name())
+	}
+	if len(c.Frontier) == 0 {
+		return nil, fmt.Errorf("checkpoint has an empty frontier")
+	}
+
+	// the children of each node are only generated once,
+	// as many saved nodes can have the same parent
+	nodes := make([]environments.Node, len(c.Nodes))
+	children := map[int]map[environments.NodeKey]environments.Node{}
+	for i, saved := range c.Nodes {
+		if saved.Parent == -1 {
+			start := e.Start()
+			if environments.KeyOf(start) != saved.Key {
+				return nil, fmt.Errorf("checkpoint starts from a different node than %s", start.Name())
+			}
+			nodes[i] = start
+			continue
+		}
+
+		parent := nodes[saved.Parent]
+		if _, ok := children[saved.Parent]; !ok {
+			children[saved.Parent] = map[environments.NodeKey]environments.Node{}
+			for _, child := range parent.Children() {
+				children[saved.Parent][environments.KeyOf(child)] = child
+			}
+		}
+
+		nodes[i] = children[saved.Parent][saved.Key]
+		if nodes[i] == nil {
+			return nil, fmt.Errorf("could not find child %d of %s from the checkpoint; the environment may have changed", i, parent.Name())
+		}
+	}
+
+	frontier := make([]environments.Node, len(c.Frontier))
+	for i, index := range c.Frontier {
+		frontier[i] = nodes[index]
+	}
+	return frontier, nil
+}
+
+// restoreQueue returns a queue of the nodes
+func restoreQueue(frontier []environments.Node, priorityMap map[environments.NodeKey]float64, config PriorityNodeQueueConfig) *PriorityNodeQueue {
+	queue := NewPriorityNodeQueue(frontier[0], priorityMap, config)
+	for _, node := range frontier[1:] {
+		heap.Push(queue, node)
+	}
+	return queue
+}
@@ -9,78 +9,103 @@ import (
 )
 
 // DepthFirst implements the depth first
-// search algorithm
+// search algorithm, and can be stepped
+// through (see Stepper)
 type DepthFirst struct {
-	queue *PriorityNodeQueue
+	stepState
 
-	depth map[string]int
+	queue *PriorityNodeQueue
 
-	iterations int
+	depth map[environments.NodeKey]float64
 }
 
-// Run runs A* on the environment and returns the result
+// Run runs depth first search on the environment and returns the result
 func (a DepthFirst) Run(ctx search.Context, e environments.Environment) (search.Result, error) {
-	a.setStart(e.Start())
-
-	node, err := a.findGoal(e)
-	if err != nil {
+	if err := a.Start(ctx, e); err != nil {
 		return search.Result{}, err
 	}
 
-	return search.Result{
-		Node:        node,
-		Iterations:  a.iterations,
-		Environment: e,
-	}, nil
+	// only steppers are asked for the expanded nodes
+	a.forgetClosed()
+	return runSteps(&a)
+}
+
+// Start starts a new search of the environment
+func (a *DepthFirst) Start(ctx search.Context, e environments.Environment) error {
+	a.stepState.start(ctx, e)
+	a.setStart(e.Start())
+	return nil
 }
 
 // initialize fields for this environment
 func (a *DepthFirst) setStart(start environments.Node) {
-	a.depth = make(map[string]int, 512)
-	a.depth[start.Name()] = 1
-
-	a.iterations = 0
+	a.depth = make(map[environments.NodeKey]float64, 512)
+	a.depth[environments.KeyOf(start)] = 1
 
 	a.queue = NewPriorityNodeQueue(start, a.depth, PriorityNodeQueueConfig{
 		HigherIsBetter: true,
 	})
 }
 
-// find and return the goal node
-func (a *DepthFirst) findGoal(e environments.Environment) (environments.Node, error) {
-	// if nothing in queue/frontier, then it is impossible
-	// to find the goal node
-	for a.queue.Len() > 0 {
-		a.iterations++
-		currentNode := heap.Pop(a.queue).(environments.Node)
+// Frontier returns the nodes waiting to be
+// expanded, in the order they'll be expanded
+func (a *DepthFirst) Frontier() []environments.Node {
+	return a.queue.Ordered()
+}
+
+// Step expands the deepest node, and returns what happened
+func (a *DepthFirst) Step() (StepEvent, bool) {
+	if a.done {
+		return StepEvent{}, true
+	}
+	if a.ctx.Cancelled() {
+		a.stop(nil, search.ErrCancelled)
+		return StepEvent{}, true
+	}
+
+	currentNode := heap.Pop(a.queue).(environments.Node)
+	event := a.expand(currentNode)
 
-		if e.IsGoalNode(currentNode) {
-			return currentNode, nil
+	if a.env.IsGoalNode(currentNode) {
+		event.Goal = true
+		a.stop(currentNode, nil)
+		return event, true
+	}
+
+	// the added children are kept in the slice of
+	// children, which isn't needed once they're added
+	children := currentNode.Children()
+	event.Added = children[:0]
+	for _, child := range children {
+		childKey := environments.KeyOf(child)
+		childIdx, inQueue := a.queue.NodeIndexes[childKey]
+		prevDepth, seenPrev := a.depth[childKey]
+		currDepth := a.depth[environments.KeyOf(currentNode)] + 1
+
+		if seenPrev && prevDepth < currDepth {
+			continue
 		}
 
-		for _, child := range currentNode.Children() {
-			childIdx, inQueue := a.queue.NodeIndexes[child.Name()]
-			prevDepth, seenPrev := a.depth[child.Name()]
-			currDepth := a.depth[currentNode.Name()] + 1
-
-			if seenPrev && prevDepth < currDepth {
-				continue
-			}
-
-			if !inQueue {
-				a.depth[child.Name()] = currDepth
-				// new node, just add it
-				heap.Push(a.queue, child)
-			} else {
-				a.depth[child.Name()] = currDepth
-				// we found a new route to the node. let's
-				// update the depth and fix its placement in the
-				// queue
-				a.queue.Frontier[childIdx] = child
-				heap.Fix(a.queue, childIdx)
-			}
+		if !inQueue {
+			a.depth[childKey] = currDepth
+			// new node, just add it
+			heap.Push(a.queue, child)
+		} else {
+			a.depth[childKey] = currDepth
+			// we found a new route to the node. let's
+			// update the depth and fix its placement in the
+			// queue
+			a.queue.Frontier[childIdx] = child
+			heap.Fix(a.queue, childIdx)
 		}
+		event.Added = append(event.Added, child)
+	}
 
+	// if nothing in queue/frontier, then it is impossible
+	// to find the goal node
+	if a.queue.Len() == 0 {
+		a.stop(nil, fmt.Errorf("frontier is empty; searched entire space, but could not find goal state"))
+		return event, true
 	}
-	return nil, fmt.Errorf("frontier is empty; searched entire space, but could not find goal state")
+	return event, false
 }
@@ -15,7 +15,7 @@ import (
 type DepthLimited struct {
 	queue *PriorityNodeQueue
 
-	depth map[string]int
+	depth map[environments.NodeKey]float64
 
 	iterations int
 
@@ -29,7 +29,7 @@ func (a DepthLimited) Run(ctx search.Context, e environments.Environment) (searc
 	}
 	a.setStart(e.Start())
 
-	node, err := a.findGoal(e)
+	node, err := a.findGoal(ctx, e)
 	if err != nil {
 		return search.Result{
 			Iterations:  a.iterations,
@@ -62,8 +62,8 @@ func (a *DepthLimited) setParams(params search.CustomSearchParams) error {
 
 // initialize fields for this environment
 func (a *DepthLimited) setStart(start environments.Node) {
-	a.depth = make(map[string]int, 512)
-	a.depth[start.Name()] = 1
+	a.depth = make(map[environments.NodeKey]float64, 512)
+	a.depth[environments.KeyOf(start)] = 1
 
 	a.iterations = 0
 
@@ -73,10 +73,13 @@ func (a *DepthLimited) setStart(start environments.Node) {
 }
 
 // find and return the goal node
-func (a *DepthLimited) findGoal(e environments.Environment) (environments.Node, error) {
+func (a *DepthLimited) findGoal(ctx search.Context, e environments.Environment) (environments.Node, error) {
 	// if nothing in queue/frontier, then it is impossible
 	// to find the goal node
 	for a.queue.Len() > 0 {
+		if ctx.Cancelled() {
+			return nil, search.ErrCancelled
+		}
 		a.iterations++
 		currentNode := heap.Pop(a.queue).(environments.Node)
 
@@ -85,11 +88,12 @@ func (a *DepthLimited) findGoal(e environments.Environment) (environments.Node,
 		}
 
 		for _, child := range currentNode.Children() {
-			childIdx, inQueue := a.queue.NodeIndexes[child.Name()]
-			prevDepth, seenPrev := a.depth[child.Name()]
-			currDepth := a.depth[currentNode.Name()] + 1
+			childKey := environments.KeyOf(child)
+			childIdx, inQueue := a.queue.NodeIndexes[childKey]
+			prevDepth, seenPrev := a.depth[childKey]
+			currDepth := a.depth[environments.KeyOf(currentNode)] + 1
 
-			if currDepth > a.limit {
+			if currDepth > float64(a.limit) {
 				continue
 			}
 
@@ -98,11 +102,11 @@ func (a *DepthLimited) findGoal(e environments.Environment) (environments.Node,
 			}
 
 			if !inQueue {
-				a.depth[child.Name()] = currDepth
+				a.depth[childKey] = currDepth
 				// new node, just add it
 				heap.Push(a.queue, child)
 			} else {
-				a.depth[child.Name()] = currDepth
+				a.depth[childKey] = currDepth
 				// we found a new route to the node. let's
 				// update the depth and fix its placement in the
 				// queue
@@ -0,0 +1,824 @@
+package algorithms
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/porgull/go-search/pkg/environments"
+)
+
+const (
+	// maxOpenRuns and maxClosedRuns are how many runs the
+	// lists spill to disk before merging them into one
+	maxOpenRuns   = 16
+	maxClosedRuns = 8
+
+	// closedIndexEvery is how many entries of a closed run
+	// there are per entry of its index, which is the most
+	// that have to be read to look up a node
+	closedIndexEvery = 64
+
+	// runBufferSize is the buffer size for reading
+	// and writing runs, which are read sequentially
+	runBufferSize = 64 << 10
+)
+
+// diskOpenList is an OpenList that keeps up to limit nodes in
+// memory, and spills the rest to disk in runs sorted by priority,
+// which Pop merges. A node pushed again is added again rather than
+// moved, so duplicates are only detected once they're popped (see
+// lists.pop). Nodes with the same priority are popped first in,
+// first out
+type diskOpenList struct {
+	env   environments.EncodableEnvironment
+	dir   string
+	limit int
+
+	entries openEntries
+	runs    []*openRun
+
+	nextSeq uint64
+	nextRun int
+	length  int
+}
+
+func newDiskOpenList(env environments.EncodableEnvironment, dir string, limit int) *diskOpenList {
+	return &diskOpenList{
+		env:   env,
+		dir:   dir,
+		limit: limit,
+	}
+}
+
+// openEntry is an encoded node on the open list,
+// where seq is the order it was pushed in
+type openEntry struct {
+	priority float64
+	seq      uint64
+	data     []byte
+}
+
+// before checks if the entry is popped before the other
+func (e openEntry) before(other openEntry) bool {
+	if e.priority != other.priority {
+		return e.priority < other.priority
+	}
+	return e.seq < other.seq
+}
+
+// openEntries implements heap.Interface for the
+// entries of the open list kept in memory
+type openEntries []openEntry
+
+func (e openEntries) Len() int            { return len(e) }
+func (e openEntries) Less(i, j int) bool  { return e[i].before(e[j]) }
+func (e openEntries) Swap(i, j int)       { e[i], e[j] = e[j], e[i] }
+func (e *openEntries) Push(x interface{}) { *e = append(*e, x.(openEntry)) }
+func (e *openEntries) Pop() interface{} {
+	old := *e
+	popped := old[len(old)-1]
+	*e = old[:len(old)-1]
+	return popped
+}
+
+// openRun is a file of entries sorted by when they're
+// popped, where head is the next entry and offset is
+// where the entries after it start
+type openRun struct {
+	file   *os.File
+	reader *bufio.Reader
+	path   string
+
+	head      openEntry
+	offset    int64
+	remaining int
+}
+
+// Push encodes the node and adds it
+func (l *diskOpenList) Push(node environments.Node, priority float64) error {
+	data, err := l.env.EncodeNode(node)
+	if err != nil {
+		return err
+	}
+
+	heap.Push(&l.entries, openEntry{priority: priority, seq: l.nextSeq, data: data})
+	l.nextSeq++
+	l.length++
+
+	if len(l.entries) >= l.limit {
+		return l.spill()
+	}
+	return nil
+}
+
+// spill writes the entries in memory to a new run,
+// merging the runs if there are too many of them
+func (l *diskOpenList) spill() error {
+	sort.Slice(l.entries, func(i, j int) bool {
+		return l.entries[i].before(l.entries[j])
+	})
+
+	entries := l.entries
+	run, err := l.writeRun(len(entries), func() (openEntry, error) {
+		next := entries[0]
+		entries = entries[1:]
+		return next, nil
+	})
+	if err != nil {
+		return err
+	}
+	l.entries = l.entries[:0]
+	l.runs = append(l.runs, run)
+
+	if len(l.runs) <= maxOpenRuns {
+		return nil
+	}
+
+	runs := l.runs
+	total := 0
+	for _, run := range runs {
+		total += run.remaining
+	}
+	merged, err := l.writeRun(total, func() (openEntry, error) {
+		first := 0
+		for i, run := range runs {
+			if run.head.before(runs[first].head) {
+				first = i
+			}
+		}
+		next := runs[first].head
+		if err := runs[first].advance(); err != nil {
+			return next, err
+		}
+		if runs[first].remaining == 0 {
+			runs = append(runs[:first], runs[first+1:]...)
+		}
+		return next, nil
+	})
+	if err != nil {
+		return err
+	}
+	l.runs = []*openRun{merged}
+	return nil
+}
+
+// writeRun writes count entries from next to a new run,
+// and opens it to be read from the start
+func (l *diskOpenList) writeRun(count int, next func() (openEntry, error)) (*openRun, error) {
+	path := filepath.Join(l.dir, fmt.Sprintf("open-%d", l.nextRun))
+	l.nextRun++
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not create run of open list: %w", err)
+	}
+
+	writer := bufio.NewWriterSize(file, runBufferSize)
+	for i := 0; i < count; i++ {
+		entry, err := next()
+		if err == nil {
+			err = writeOpenEntry(writer, entry)
+		}
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("could not write run of open list: %w", err)
+		}
+	}
+	if err = writer.Flush(); err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("could not write run of open list: %w", err)
+	}
+
+	run := &openRun{
+		file:      file,
+		reader:    bufio.NewReaderSize(file, runBufferSize),
+		path:      path,
+		remaining: count + 1,
+	}
+	return run, run.advance()
+}
+
+// advance reads the next entry of the run into head,
+// and closes and removes the run once it's empty
+func (r *openRun) advance() error {
+	r.remaining--
+	if r.remaining == 0 {
+		r.file.Close()
+		return os.Remove(r.path)
+	}
+
+	entry, size, err := readOpenEntry(r.reader)
+	if err != nil {
+		return fmt.Errorf("could not read run of open list: %w", err)
+	}
+	r.head = entry
+	r.offset += size
+	return nil
+}
+
+// Pop decodes and returns the entry with the lowest
+// priority in memory or at the head of a run
+func (l *diskOpenList) Pop() (environments.Node, float64, error) {
+	if l.length == 0 {
+		return nil, 0, fmt.Errorf("open list is empty")
+	}
+
+	first := -1
+	for i, run := range l.runs {
+		if first == -1 || run.head.before(l.runs[first].head) {
+			first = i
+		}
+	}
+
+	var entry openEntry
+	if first == -1 || (len(l.entries) > 0 && l.entries[0].before(l.runs[first].head)) {
+		entry = heap.Pop(&l.entries).(openEntry)
+	} else {
+		run := l.runs[first]
+		entry = run.head
+		if err := run.advance(); err != nil {
+			return nil, 0, err
+		}
+		if run.remaining == 0 {
+			l.runs = append(l.runs[:first], l.runs[first+1:]...)
+		}
+	}
+	l.length--
+
+	node, err := l.env.DecodeNode(entry.data)
+	if err != nil {
+		return nil, 0, err
+	}
+	return node, entry.priority, nil
+}
+
+func (l *diskOpenList) Len() int {
+	return l.length
+}
+
+// Nodes reads every entry, including those on disk and
+// those that will be skipped as they were pushed again,
+// so it is only meant for small searches
+func (l *diskOpenList) Nodes() ([]environments.Node, error) {
+	entries := append(openEntries(nil), l.entries...)
+	for _, run := range l.runs {
+		entries = append(entries, run.head)
+
+		info, err := run.file.Stat()
+		if err != nil {
+			return nil, fmt.Errorf("could not read run of open list: %w", err)
+		}
+		reader := bufio.NewReader(io.NewSectionReader(run.file, run.offset, info.Size()-run.offset))
+		for i := 1; i < run.remaining; i++ {
+			entry, _, err := readOpenEntry(reader)
+			if err != nil {
+				return nil, fmt.Errorf("could not read run of open list: %w", err)
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].before(entries[j])
+	})
+
+	nodes := make([]environments.Node, len(entries))
+	for i, entry := range entries {
+		node, err := l.env.DecodeNode(entry.data)
+		if err != nil {
+			return nil, err
+		}
+		nodes[i] = node
+	}
+	return nodes, nil
+}
+
+func (l *diskOpenList) Close() error {
+	for _, run := range l.runs {
+		run.file.Close()
+	}
+	l.runs = nil
+	l.entries = nil
+	l.length = 0
+	return nil
+}
+
+// writeOpenEntry writes the priority, the order
+// and the length of the node before the node
+func writeOpenEntry(w *bufio.Writer, entry openEntry) error {
+	var header [8 + 2*binary.MaxVarintLen64]byte
+	binary.BigEndian.PutUint64(header[:], math.Float64bits(entry.priority))
+	size := 8 + binary.PutUvarint(header[8:], entry.seq)
+	size += binary.PutUvarint(header[size:], uint64(len(entry.data)))
+
+	if _, err := w.Write(header[:size]); err != nil {
+		return err
+	}
+	_, err := w.Write(entry.data)
+	return err
+}
+
+// readOpenEntry reads an entry written by writeOpenEntry,
+// and returns how many bytes it took up
+func readOpenEntry(r *bufio.Reader) (openEntry, int64, error) {
+	var priority [8]byte
+	if _, err := io.ReadFull(r, priority[:]); err != nil {
+		return openEntry{}, 0, err
+	}
+	entry := openEntry{priority: math.Float64frombits(binary.BigEndian.Uint64(priority[:]))}
+
+	counter := &countingByteReader{reader: r, read: 8}
+	var err error
+	if entry.seq, err = binary.ReadUvarint(counter); err != nil {
+		return openEntry{}, 0, err
+	}
+	length, err := binary.ReadUvarint(counter)
+	if err != nil {
+		return openEntry{}, 0, err
+	}
+
+	entry.data = make([]byte, length)
+	if _, err = io.ReadFull(r, entry.data); err != nil {
+		return openEntry{}, 0, err
+	}
+	return entry, counter.read + int64(length), nil
+}
+
+// countingByteReader counts the bytes read, so the
+// size of the varints read from it is known
+type countingByteReader struct {
+	reader io.ByteReader
+	read   int64
+}
+
+func (r *countingByteReader) ReadByte() (byte, error) {
+	b, err := r.reader.ReadByte()
+	if err == nil {
+		r.read++
+	}
+	return b, err
+}
+
+// diskClosedList is a ClosedList that keeps up to limit nodes
+// in memory, and spills the rest to disk in runs sorted by key.
+// Looking a node up only reads the part of each run its key
+// could be in, and skips the runs whose bloom filter rules it
+// out. The key of every node's parent is stored as well, so
+// the path to a node can be found without keeping the nodes
+type diskClosedList struct {
+	env   environments.EncodableEnvironment
+	dir   string
+	limit int
+
+	entries map[environments.NodeKey]closedEntry
+
+	// runs are in the order they were written, and an entry in
+	// a later run replaces those of the same key in earlier ones
+	runs []*closedRun
+
+	nextRun int
+	length  int
+}
+
+func newDiskClosedList(env environments.EncodableEnvironment, dir string, limit int) *diskClosedList {
+	return &diskClosedList{
+		env:     env,
+		dir:     dir,
+		limit:   limit,
+		entries: make(map[environments.NodeKey]closedEntry, 512),
+	}
+}
+
+// closedEntry is the cost a node was reached with,
+// and the key of its parent if it has one
+type closedEntry struct {
+	cost      float64
+	parent    environments.NodeKey
+	hasParent bool
+}
+
+// closedRun is a file of entries sorted by key, with the
+// key and offset of every closedIndexEvery-th entry
+type closedRun struct {
+	file   *os.File
+	path   string
+	size   int64
+	index  []closedIndexEntry
+	filter bloomFilter
+}
+
+type closedIndexEntry struct {
+	key    []byte
+	offset int64
+}
+
+// Reach records the node, only if it wasn't reached
+// with the same or a lower cost before
+func (l *diskClosedList) Reach(node environments.Node, cost float64) (bool, error) {
+	key := environments.KeyOf(node)
+	previous, ok, err := l.lookup(key)
+	if err != nil {
+		return false, err
+	}
+	if ok && previous.cost <= cost {
+		return false, nil
+	}
+	if !ok {
+		l.length++
+	}
+
+	entry := closedEntry{cost: cost}
+	if parent := node.Parent(); parent != nil {
+		entry.parent = environments.KeyOf(parent)
+		entry.hasParent = true
+	}
+	l.entries[key] = entry
+
+	if len(l.entries) >= l.limit {
+		return true, l.spill()
+	}
+	return true, nil
+}
+
+func (l *diskClosedList) Cost(key environments.NodeKey) (float64, bool, error) {
+	entry, ok, err := l.lookup(key)
+	return entry.cost, ok, err
+}
+
+// lookup returns the latest entry of the key
+func (l *diskClosedList) lookup(key environments.NodeKey) (closedEntry, bool, error) {
+	if entry, ok := l.entries[key]; ok {
+		return entry, true, nil
+	}
+	if len(l.runs) == 0 {
+		return closedEntry{}, false, nil
+	}
+
+	encoded, _ := key.MarshalBinary()
+	hash := key.Hash()
+	for i := len(l.runs) - 1; i >= 0; i-- {
+		run := l.runs[i]
+		if !run.filter.has(hash) {
+			continue
+		}
+
+		// the key can only be after the last indexed key
+		// before it, and before the next indexed key
+		block := sort.Search(len(run.index), func(j int) bool {
+			return bytes.Compare(run.index[j].key, encoded) > 0
+		}) - 1
+		if block < 0 {
+			continue
+		}
+		end := run.size
+		if block+1 < len(run.index) {
+			end = run.index[block+1].offset
+		}
+
+		reader := bufio.NewReader(io.NewSectionReader(run.file, run.index[block].offset, end-run.index[block].offset))
+		for {
+			entryKey, entry, err := readClosedEntry(reader)
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return closedEntry{}, false, fmt.Errorf("could not read run of closed list: %w", err)
+			}
+			if comparison := bytes.Compare(entryKey, encoded); comparison == 0 {
+				return entry, true, nil
+			} else if comparison > 0 {
+				break
+			}
+		}
+	}
+	return closedEntry{}, false, nil
+}
+
+// spill writes the entries in memory to a new run,
+// merging the runs if there are too many of them
+func (l *diskClosedList) spill() error {
+	type sortedEntry struct {
+		key     environments.NodeKey
+		encoded []byte
+		entry   closedEntry
+	}
+	sorted := make([]sortedEntry, 0, len(l.entries))
+	for key, entry := range l.entries {
+		encoded, _ := key.MarshalBinary()
+		sorted = append(sorted, sortedEntry{key: key, encoded: encoded, entry: entry})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].encoded, sorted[j].encoded) < 0
+	})
+
+	run, err := l.writeRun(len(sorted), func() ([]byte, closedEntry, error) {
+		next := sorted[0]
+		sorted = sorted[1:]
+		return next.encoded, next.entry, nil
+	})
+	if err != nil {
+		return err
+	}
+	l.entries = make(map[environments.NodeKey]closedEntry, len(l.entries))
+	l.runs = append(l.runs, run)
+
+	if len(l.runs) <= maxClosedRuns {
+		return nil
+	}
+	return l.merge()
+}
+
+// merge merges the runs into one,
+// keeping the latest entry of each key
+func (l *diskClosedList) merge() error {
+	type mergedRun struct {
+		reader *bufio.Reader
+		key    []byte
+		entry  closedEntry
+	}
+
+	runs := make([]*mergedRun, 0, len(l.runs))
+	total := 0
+	for _, run := range l.runs {
+		if _, err := run.file.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("could not read run of closed list: %w", err)
+		}
+		merged := &mergedRun{reader: bufio.NewReaderSize(run.file, runBufferSize)}
+		key, entry, err := readClosedEntry(merged.reader)
+		if err == io.EOF {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("could not read run of closed list: %w", err)
+		}
+		merged.key, merged.entry = key, entry
+		runs = append(runs, merged)
+		total += int(run.filter.count)
+	}
+
+	// total over counts the entries in the merged run by the
+	// duplicates, but writeRun stops once next returns nil
+	run, err := l.writeRun(total, func() ([]byte, closedEntry, error) {
+		if len(runs) == 0 {
+			return nil, closedEntry{}, nil
+		}
+
+		// later runs come later, so the last of
+		// the runs with the lowest key is the latest
+		first := 0
+		for i, run := range runs {
+			if bytes.Compare(run.key, runs[first].key) <= 0 {
+				first = i
+			}
+		}
+		key, entry := runs[first].key, runs[first].entry
+
+		for i := 0; i < len(runs); i++ {
+			if !bytes.Equal(runs[i].key, key) {
+				continue
+			}
+			next, nextEntry, err := readClosedEntry(runs[i].reader)
+			if err == io.EOF {
+				runs = append(runs[:i], runs[i+1:]...)
+				i--
+				continue
+			} else if err != nil {
+				return nil, closedEntry{}, err
+			}
+			runs[i].key, runs[i].entry = next, nextEntry
+		}
+		return key, entry, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, old := range l.runs {
+		old.file.Close()
+		os.Remove(old.path)
+	}
+	l.runs = []*closedRun{run}
+	return nil
+}
+
+// writeRun writes up to count entries from next, which are
+// sorted by their encoded keys, to a new run, and indexes it
+func (l *diskClosedList) writeRun(count int, next func() ([]byte, closedEntry, error)) (*closedRun, error) {
+	path := filepath.Join(l.dir, fmt.Sprintf("closed-%d", l.nextRun))
+	l.nextRun++
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not create run of closed list: %w", err)
+	}
+
+	run := &closedRun{
+		file:   file,
+		path:   path,
+		filter: newBloomFilter(count),
+	}
+	writer := bufio.NewWriterSize(file, runBufferSize)
+	for i := 0; i < count; i++ {
+		encoded, entry, err := next()
+		if err == nil && encoded == nil {
+			break
+		}
+
+		var key environments.NodeKey
+		if err == nil {
+			err = key.UnmarshalBinary(encoded)
+		}
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("could not write run of closed list: %w", err)
+		}
+
+		if i%closedIndexEvery == 0 {
+			run.index = append(run.index, closedIndexEntry{key: encoded, offset: run.size})
+		}
+		run.filter.add(key.Hash())
+
+		size, err := writeClosedEntry(writer, encoded, entry)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("could not write run of closed list: %w", err)
+		}
+		run.size += size
+	}
+	if err = writer.Flush(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("could not write run of closed list: %w", err)
+	}
+	return run, nil
+}
+
+// Path follows the parents of the node back to the start,
+// and finds the nodes on the way again from the start
+func (l *diskClosedList) Path(node environments.Node) (environments.Node, error) {
+	keys := []environments.NodeKey{environments.KeyOf(node)}
+	for {
+		entry, ok, err := l.lookup(keys[len(keys)-1])
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("could not find %s on the closed list", node.Name())
+		}
+		if !entry.hasParent {
+			break
+		}
+
+		keys = append(keys, entry.parent)
+		if len(keys) > l.length {
+			return nil, fmt.Errorf("parents of %s on the closed list form a cycle", node.Name())
+		}
+	}
+
+	current := l.env.Start()
+	if environments.KeyOf(current) != keys[len(keys)-1] {
+		return nil, fmt.Errorf("path to %s on the closed list doesn't begin at the start", node.Name())
+	}
+	for i := len(keys) - 2; i >= 0; i-- {
+		var next environments.Node
+		for _, child := range current.Children() {
+			if environments.KeyOf(child) == keys[i] {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return nil, fmt.Errorf("could not find the path to %s again from the start", node.Name())
+		}
+		current = next
+	}
+	return current, nil
+}
+
+func (l *diskClosedList) Len() int {
+	return l.length
+}
+
+func (l *diskClosedList) Close() error {
+	for _, run := range l.runs {
+		run.file.Close()
+	}
+	l.runs = nil
+	l.entries = nil
+	return nil
+}
+
+// writeClosedEntry writes the key and the entry, with the length
+// of the parent's key plus one (or zero if it has no parent)
+// before it, and returns how many bytes it took up
+func writeClosedEntry(w *bufio.Writer, key []byte, entry closedEntry) (int64, error) {
+	var parent []byte
+	if entry.hasParent {
+		parent, _ = entry.parent.MarshalBinary()
+	}
+
+	var header [binary.MaxVarintLen64]byte
+	buf := make([]byte, 0, 2*binary.MaxVarintLen64+len(key)+8+len(parent))
+	buf = append(buf, header[:binary.PutUvarint(header[:], uint64(len(key)))]...)
+	buf = append(buf, key...)
+
+	var cost [8]byte
+	binary.BigEndian.PutUint64(cost[:], math.Float64bits(entry.cost))
+	buf = append(buf, cost[:]...)
+
+	parentLength := uint64(0)
+	if entry.hasParent {
+		parentLength = uint64(len(parent)) + 1
+	}
+	buf = append(buf, header[:binary.PutUvarint(header[:], parentLength)]...)
+	buf = append(buf, parent...)
+
+	_, err := w.Write(buf)
+	return int64(len(buf)), err
+}
+
+// readClosedEntry reads an entry written by writeClosedEntry,
+// returning io.EOF if there aren't any entries left
+func readClosedEntry(r *bufio.Reader) ([]byte, closedEntry, error) {
+	keyLength, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, closedEntry{}, err
+	}
+
+	key := make([]byte, keyLength)
+	var cost [8]byte
+	if _, err = io.ReadFull(r, key); err == nil {
+		_, err = io.ReadFull(r, cost[:])
+	}
+	if err != nil {
+		return nil, closedEntry{}, unexpectedEOF(err)
+	}
+	entry := closedEntry{cost: math.Float64frombits(binary.BigEndian.Uint64(cost[:]))}
+
+	parentLength, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, closedEntry{}, unexpectedEOF(err)
+	}
+	if parentLength > 0 {
+		parent := make([]byte, parentLength-1)
+		if _, err = io.ReadFull(r, parent); err != nil {
+			return nil, closedEntry{}, unexpectedEOF(err)
+		}
+		if err = entry.parent.UnmarshalBinary(parent); err != nil {
+			return nil, closedEntry{}, err
+		}
+		entry.hasParent = true
+	}
+	return key, entry, nil
+}
+
+// unexpectedEOF turns io.EOF into io.ErrUnexpectedEOF,
+// for when an entry ends part of the way through
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// bloomFilter tells if a run of a closed list might have
+// a key, with about a 1% chance of false positives
+type bloomFilter struct {
+	bits  []uint64
+	count uint64
+}
+
+// bloomHashes is the number of bits set per key
+const bloomHashes = 7
+
+func newBloomFilter(keys int) bloomFilter {
+	// about 10 bits per key
+	return bloomFilter{bits: make([]uint64, keys/6+1)}
+}
+
+func (f *bloomFilter) add(hash uint64) {
+	f.count++
+	size := uint64(len(f.bits) * 64)
+	for i, bit := uint64(0), hash; i < bloomHashes; i, bit = i+1, bit+(hash>>32|1) {
+		f.bits[bit%size/64] |= 1 << (bit % size % 64)
+	}
+}
+
+func (f *bloomFilter) has(hash uint64) bool {
+	size := uint64(len(f.bits) * 64)
+	for i, bit := uint64(0), hash; i < bloomHashes; i, bit = i+1, bit+(hash>>32|1) {
+		if f.bits[bit%size/64]&(1<<(bit%size%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
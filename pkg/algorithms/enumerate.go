@@ -0,0 +1,503 @@
+package algorithms
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+// EnumerateConfig defines optional
+// arguments when enumerating states
+type EnumerateConfig struct {
+	// Dir is the directory the layers are written to, as
+	// layer-<depth> files. If it's empty, they're written
+	// to a temporary directory, which is removed after
+	Dir string
+
+	// MemoryLimit is how many nodes are kept in memory
+	// before they're sorted and spilled to disk,
+	// defaulting to 1048576
+	MemoryLimit int
+
+	// OnLayer is called with the depth and size
+	// of every layer once it's written, if set
+	OnLayer func(depth, size int)
+}
+
+// Enumeration contains the sizes of the layers of
+// the states reachable from the start of an environment
+type Enumeration struct {
+	// Layers contains the number of states at each depth,
+	// starting with the start, which is at depth 0
+	Layers []int
+
+	// States is the number of reachable states
+	States int
+
+	// Diameter is the depth of the deepest state,
+	// i.e. how many steps it takes to reach every
+	// state from the start
+	Diameter int
+}
+
+// Enumerate runs breadth first search from the start of the
+// environment until every reachable state has been found. It
+// only keeps one layer in memory at a time, so it can enumerate
+// state spaces larger than RAM: the children of each layer are
+// sorted in runs, and merged with the states found before (from
+// disk) to remove duplicates, which are then written to disk as
+// the next layer. Each layer-<depth> file lists its states sorted
+// by key, each as the uvarint length of its binary key (see
+// environments.NodeKey.MarshalBinary), the key, the uvarint
+// length of the encoded node (see EncodableEnvironment) and the
+// encoded node; see ReadLayer
+func Enumerate(ctx search.Context, e environments.Environment, config EnumerateConfig) (*Enumeration, error) {
+	encodable, ok := e.(environments.EncodableEnvironment)
+	if !ok {
+		return nil, fmt.Errorf("environment %s can't be stored on disk", e.Name())
+	}
+
+	if config.MemoryLimit == 0 {
+		config.MemoryLimit = 1 << 20
+	} else if config.MemoryLimit < 1 {
+		return nil, fmt.Errorf("memory limit must be at least 1, but was %d", config.MemoryLimit)
+	}
+
+	dir := config.Dir
+	if dir == "" {
+		var err error
+		if dir, err = os.MkdirTemp("", "go-search-layers-"); err != nil {
+			return nil, fmt.Errorf("could not create layer directory: %w", err)
+		}
+		defer os.RemoveAll(dir)
+	} else if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("could not create layer directory: %w", err)
+	}
+
+	scratch, err := os.MkdirTemp(dir, "scratch-")
+	if err != nil {
+		return nil, fmt.Errorf("could not create scratch directory: %w", err)
+	}
+	defer os.RemoveAll(scratch)
+
+	en := &enumerator{
+		ctx:     ctx,
+		env:     encodable,
+		dir:     dir,
+		scratch: scratch,
+		limit:   config.MemoryLimit,
+	}
+	if err = en.writeStart(); err != nil {
+		return nil, err
+	}
+
+	enumeration := &Enumeration{Layers: []int{1}, States: 1}
+	if config.OnLayer != nil {
+		config.OnLayer(0, 1)
+	}
+	for depth := 1; ; depth++ {
+		size, err := en.expand(depth)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			break
+		}
+
+		enumeration.Layers = append(enumeration.Layers, size)
+		enumeration.States += size
+		enumeration.Diameter = depth
+		if config.OnLayer != nil {
+			config.OnLayer(depth, size)
+		}
+	}
+	return enumeration, nil
+}
+
+// ReadLayer calls the function with every node of a layer
+// written by Enumerate, which have no parents, in the order
+// of their keys, and stops at the first error it returns
+func ReadLayer(r io.Reader, e environments.EncodableEnvironment, fn func(environments.Node) error) error {
+	reader := &layerReader{reader: bufio.NewReaderSize(r, runBufferSize)}
+	for {
+		if err := reader.next(); err != nil {
+			return err
+		}
+		if reader.done {
+			return nil
+		}
+
+		node, err := e.DecodeNode(reader.data)
+		if err != nil {
+			return err
+		}
+		if err = fn(node); err != nil {
+			return err
+		}
+	}
+}
+
+// enumerator contains what Enumerate keeps between layers. The
+// keys of every state found so far are in the visited file in
+// the scratch directory, sorted so the children of a layer can
+// be merged with them
+type enumerator struct {
+	ctx     search.Context
+	env     environments.EncodableEnvironment
+	dir     string
+	scratch string
+	limit   int
+
+	nextRun int
+}
+
+// layerPath returns the path of the layer at the depth
+func (en *enumerator) layerPath(depth int) string {
+	return filepath.Join(en.dir, fmt.Sprintf("layer-%d", depth))
+}
+
+// writeStart writes the start as the first layer,
+// and as the only state that has been visited
+func (en *enumerator) writeStart() error {
+	start := en.env.Start()
+	key, _ := environments.KeyOf(start).MarshalBinary()
+	data, err := en.env.EncodeNode(start)
+	if err != nil {
+		return err
+	}
+
+	if err = writeLayerFile(en.layerPath(0), []layerEntry{{key: key, data: data}}); err != nil {
+		return err
+	}
+	return writeLayerFile(filepath.Join(en.scratch, "visited"), []layerEntry{{key: key}})
+}
+
+// layerEntry is the binary key of a node
+// and the encoded node, if it is kept
+type layerEntry struct {
+	key  []byte
+	data []byte
+}
+
+// expand writes the children of the layer before the depth that
+// haven't been visited as the layer at the depth, and returns its
+// size. The layer is removed if it's empty
+func (en *enumerator) expand(depth int) (int, error) {
+	parents, err := os.Open(en.layerPath(depth - 1))
+	if err != nil {
+		return 0, fmt.Errorf("could not open layer %d: %w", depth-1, err)
+	}
+	defer parents.Close()
+
+	// the children are sorted and spilled to a run whenever there
+	// are too many in memory, and the runs are merged whenever
+	// there are too many of them
+	var runs []string
+	children := make([]layerEntry, 0, 512)
+	spill := func() error {
+		run, err := en.writeRun(children)
+		if err != nil {
+			return err
+		}
+		children = children[:0]
+		runs = append(runs, run)
+
+		if len(runs) <= maxOpenRuns {
+			return nil
+		}
+		merged, err := en.mergeToRun(runs)
+		runs = []string{merged}
+		return err
+	}
+
+	err = ReadLayer(parents, en.env, func(parent environments.Node) error {
+		if en.ctx.Cancelled() {
+			return search.ErrCancelled
+		}
+
+		for _, child := range parent.Children() {
+			key, _ := environments.KeyOf(child).MarshalBinary()
+			data, err := en.env.EncodeNode(child)
+			if err != nil {
+				return err
+			}
+
+			children = append(children, layerEntry{key: key, data: data})
+			if len(children) >= en.limit {
+				if err = spill(); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err == nil && len(children) > 0 {
+		err = spill()
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := en.subtractVisited(runs, en.layerPath(depth))
+	if err == nil && size == 0 {
+		err = os.Remove(en.layerPath(depth))
+	}
+	return size, err
+}
+
+// writeRun sorts the entries by key, and writes them to a new
+// run in the scratch directory without the duplicates
+func (en *enumerator) writeRun(entries []layerEntry) (string, error) {
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+
+	unique := entries[:0]
+	for i, entry := range entries {
+		if i == 0 || !bytes.Equal(entry.key, entries[i-1].key) {
+			unique = append(unique, entry)
+		}
+	}
+
+	path := en.runPath()
+	return path, writeLayerFile(path, unique)
+}
+
+// runPath returns the path of a new run
+func (en *enumerator) runPath() string {
+	en.nextRun++
+	return filepath.Join(en.scratch, fmt.Sprintf("run-%d", en.nextRun))
+}
+
+// mergeToRun merges the runs into a new one,
+// and removes them
+func (en *enumerator) mergeToRun(runs []string) (string, error) {
+	path := en.runPath()
+	out, err := newLayerWriter(path)
+	if err != nil {
+		return "", err
+	}
+
+	err = mergeLayerRuns(runs, out.write)
+	if closeErr := out.close(); err == nil {
+		err = closeErr
+	}
+	return path, err
+}
+
+// subtractVisited merges the runs, and writes the children
+// in them that weren't visited to the layer, adding them to
+// the visited file. It returns the number of children written
+func (en *enumerator) subtractVisited(runs []string, layerPath string) (int, error) {
+	visitedPath := filepath.Join(en.scratch, "visited")
+	visitedFile, err := os.Open(visitedPath)
+	if err != nil {
+		return 0, fmt.Errorf("could not open visited states: %w", err)
+	}
+	defer visitedFile.Close()
+	visited := &layerReader{reader: bufio.NewReaderSize(visitedFile, runBufferSize)}
+	if err = visited.next(); err != nil {
+		return 0, err
+	}
+
+	layer, err := newLayerWriter(layerPath)
+	if err != nil {
+		return 0, err
+	}
+	nextVisited, err := newLayerWriter(visitedPath + ".next")
+	if err != nil {
+		layer.close()
+		return 0, err
+	}
+
+	size := 0
+	err = mergeLayerRuns(runs, func(entry layerEntry) error {
+		// the visited keys are copied up to the child,
+		// which is only new if it isn't one of them
+		for !visited.done && bytes.Compare(visited.key, entry.key) < 0 {
+			if err := nextVisited.write(layerEntry{key: visited.key}); err != nil {
+				return err
+			}
+			if err := visited.next(); err != nil {
+				return err
+			}
+		}
+		if !visited.done && bytes.Equal(visited.key, entry.key) {
+			return nil
+		}
+
+		size++
+		if err := layer.write(entry); err != nil {
+			return err
+		}
+		return nextVisited.write(layerEntry{key: entry.key})
+	})
+	for err == nil && !visited.done {
+		if err = nextVisited.write(layerEntry{key: visited.key}); err == nil {
+			err = visited.next()
+		}
+	}
+
+	if closeErr := layer.close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := nextVisited.close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(visitedPath+".next", visitedPath)
+	}
+	return size, err
+}
+
+// mergeLayerRuns calls emit with the entries of the runs in
+// the order of their keys, only once per key, and removes
+// the runs once they're merged
+func mergeLayerRuns(runs []string, emit func(layerEntry) error) error {
+	readers := make([]*layerReader, 0, len(runs))
+	defer func() {
+		for _, reader := range readers {
+			reader.file.Close()
+		}
+		for _, run := range runs {
+			os.Remove(run)
+		}
+	}()
+
+	for _, run := range runs {
+		file, err := os.Open(run)
+		if err != nil {
+			return fmt.Errorf("could not open run: %w", err)
+		}
+		reader := &layerReader{file: file, reader: bufio.NewReaderSize(file, runBufferSize)}
+		readers = append(readers, reader)
+		if err = reader.next(); err != nil {
+			return err
+		}
+	}
+
+	for {
+		var first *layerReader
+		for _, reader := range readers {
+			if !reader.done && (first == nil || bytes.Compare(reader.key, first.key) < 0) {
+				first = reader
+			}
+		}
+		if first == nil {
+			return nil
+		}
+
+		entry := layerEntry{key: first.key, data: first.data}
+		if err := emit(entry); err != nil {
+			return err
+		}
+		for _, reader := range readers {
+			for !reader.done && bytes.Equal(reader.key, entry.key) {
+				if err := reader.next(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// layerReader reads the entries of a layer or run,
+// where key and data are of the current entry
+type layerReader struct {
+	file   *os.File
+	reader *bufio.Reader
+
+	key  []byte
+	data []byte
+	done bool
+}
+
+// next reads the next entry, setting done
+// if there aren't any entries left
+func (r *layerReader) next() error {
+	keyLength, err := binary.ReadUvarint(r.reader)
+	if err == io.EOF {
+		r.done = true
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("could not read layer: %w", err)
+	}
+
+	key := make([]byte, keyLength)
+	if _, err = io.ReadFull(r.reader, key); err != nil {
+		return fmt.Errorf("could not read layer: %w", unexpectedEOF(err))
+	}
+	dataLength, err := binary.ReadUvarint(r.reader)
+	if err != nil {
+		return fmt.Errorf("could not read layer: %w", unexpectedEOF(err))
+	}
+	data := make([]byte, dataLength)
+	if _, err = io.ReadFull(r.reader, data); err != nil {
+		return fmt.Errorf("could not read layer: %w", unexpectedEOF(err))
+	}
+
+	r.key, r.data = key, data
+	return nil
+}
+
+// layerWriter writes the entries of a layer or run
+type layerWriter struct {
+	file   *os.File
+	writer *bufio.Writer
+}
+
+func newLayerWriter(path string) (*layerWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not create %s: %w", filepath.Base(path), err)
+	}
+	return &layerWriter{file: file, writer: bufio.NewWriterSize(file, runBufferSize)}, nil
+}
+
+// write writes the lengths of the key and data before them
+func (w *layerWriter) write(entry layerEntry) error {
+	var header [binary.MaxVarintLen64]byte
+	if _, err := w.writer.Write(header[:binary.PutUvarint(header[:], uint64(len(entry.key)))]); err != nil {
+		return err
+	}
+	if _, err := w.writer.Write(entry.key); err != nil {
+		return err
+	}
+	if _, err := w.writer.Write(header[:binary.PutUvarint(header[:], uint64(len(entry.data)))]); err != nil {
+		return err
+	}
+	_, err := w.writer.Write(entry.data)
+	return err
+}
+
+// close flushes and closes the file
+func (w *layerWriter) close() error {
+	err := w.writer.Flush()
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// writeLayerFile writes the entries to a new file
+func writeLayerFile(path string, entries []layerEntry) error {
+	w, err := newLayerWriter(path)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err = w.write(entry); err != nil {
+			w.close()
+			return err
+		}
+	}
+	return w.close()
+}
@@ -0,0 +1,267 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+// Expectimax implements the expectimax search algorithm
+// for stochastic environments (see environments.ChanceNode).
+// It picks the children with the lowest expected cost to
+// the goal, and models any adversary (see
+// environments.AdversaryNode) as picking a child uniformly
+// at random. It can take the `depth_limit` custom argument;
+// by default it searches until every path reaches the goal
+// or revisits a state, which never reaches the goal
+type Expectimax struct {
+	expectimaxSearch
+}
+
+// Expectiminimax implements the depth limited expectiminimax
+// search algorithm, which is expectimax where adversary nodes
+// pick the child with the highest expected cost. It requires
+// the `depth_limit` custom argument to be passed
+type Expectiminimax struct {
+	expectimaxSearch
+}
+
+// Run runs expectimax on the environment and returns the
+// most likely path when following the best decisions
+func (a Expectimax) Run(ctx search.Context, e environments.Environment) (search.Result, error) {
+	if err := a.setParams(ctx.CustomSearchParams, false); err != nil {
+		return search.Result{}, err
+	}
+
+	return a.getResult(ctx, e)
+}
+
+// Run runs expectiminimax on the environment and returns the
+// most likely path when following the best decisions
+func (a Expectiminimax) Run(ctx search.Context, e environments.Environment) (search.Result, error) {
+	if err := a.setParams(ctx.CustomSearchParams, true); err != nil {
+		return search.Result{}, err
+	}
+	a.minimax = true
+
+	return a.getResult(ctx, e)
+}
+
+// expectimaxSearch contains the logic shared between
+// expectimax and expectiminimax
+type expectimaxSearch struct {
+	// minimax is true if adversaries pick the worst
+	// child rather than a uniformly random one
+	minimax bool
+
+	// limit is the maximum depth to search,
+	// or -1 if there isn't one
+	limit int
+
+	// path contains the names of the nodes on
+	// the path currently being evaluated
+	path map[environments.NodeKey]bool
+
+	// ctx is checked for cancellation before evaluating nodes
+	ctx search.Context
+
+	iterations int
+}
+
+func (a *expectimaxSearch) setParams(params search.CustomSearchParams, required bool) error {
+	limit, ok := params["depth_limit"]
+	if !ok {
+		if required {
+			return fmt.Errorf("'depth_limit' custom parameters was not supplied")
+		}
+		limit = "-1"
+	}
+
+	parsedLimit, err := strconv.ParseInt(limit, 10, 32)
+	if err != nil {
+		return fmt.Errorf("Could not parse 'depth_limit' as integer: %w", err)
+	}
+
+	a.limit = int(parsedLimit)
+
+	return nil
+}
+
+// getResult evaluates the start node, then follows the
+// best decisions (and most likely outcomes) from it
+func (a *expectimaxSearch) getResult(ctx search.Context, e environments.Environment) (search.Result, error) {
+	a.path = make(map[environments.NodeKey]bool, 64)
+	a.ctx = ctx
+	a.iterations = 0
+
+	start := e.Start()
+	expectedCost := a.value(e, start, 0)
+	if ctx.Cancelled() {
+		return search.Result{}, search.ErrCancelled
+	}
+	if math.IsInf(expectedCost, 1) {
+		return search.Result{
+			Iterations:  a.iterations,
+			Environment: e,
+		}, fmt.Errorf("explored entire search space, but could not find goal node")
+	}
+
+	node, err := a.followBest(e, start)
+	if err != nil {
+		return search.Result{
+			Iterations:  a.iterations,
+			Environment: e,
+		}, err
+	}
+
+	return search.Result{
+		Node:        node,
+		Iterations:  a.iterations,
+		Environment: e,
+		CustomResultStats: map[string]string{
+			"expected_cost": strconv.FormatFloat(expectedCost, 'f', -1, 64),
+		},
+	}, nil
+}
+
+// followBest walks from the node by taking the best child
+// and the most likely outcome until it reaches the goal, and
+// fails if it can't go further or would revisit a node
+func (a *expectimaxSearch) followBest(e environments.Environment, node environments.Node) (environments.Node, error) {
+	visited := make(map[environments.NodeKey]bool, 64)
+	depth := 0
+	for !e.IsGoalNode(node) {
+		if visited[environments.KeyOf(node)] {
+			return nil, fmt.Errorf("most likely path loops back to %s before reaching the goal", node.Name())
+		}
+		if a.limit != -1 && depth >= a.limit {
+			return nil, fmt.Errorf("most likely path reaches the depth limit of %d at %s before reaching the goal", a.limit, node.Name())
+		}
+		visited[environments.KeyOf(node)] = true
+
+		child, _ := a.bestChild(e, node, depth)
+		if child == nil {
+			return nil, fmt.Errorf("most likely path reaches %s, which has no children", node.Name())
+		}
+		depth++
+
+		node = child
+		if outcome, ok := mostLikelyOutcome(child); ok {
+			node = outcome
+		}
+	}
+	return node, nil
+}
+
+// value returns the expected cost of reaching
+// the goal from the node, which is meaningless
+// once the search was cancelled
+func (a *expectimaxSearch) value(e environments.Environment, node environments.Node, depth int) float64 {
+	if a.ctx.Cancelled() {
+		return math.Inf(1)
+	}
+	a.iterations++
+
+	if e.IsGoalNode(node) {
+		return 0
+	}
+
+	// going around a cycle never reaches the goal
+	if a.path[environments.KeyOf(node)] {
+		return math.Inf(1)
+	}
+	if a.limit != -1 && depth >= a.limit {
+		return environments.HeuristicOf(node)
+	}
+
+	a.path[environments.KeyOf(node)] = true
+	defer delete(a.path, environments.KeyOf(node))
+
+	_, value := a.bestChild(e, node, depth)
+	return value
+}
+
+// bestChild returns the child that would be picked at
+// the node and the expected cost of the node
+func (a *expectimaxSearch) bestChild(e environments.Environment, node environments.Node, depth int) (environments.Node, float64) {
+	children := node.Children()
+	if len(children) == 0 {
+		return nil, math.Inf(1)
+	}
+
+	adversary := false
+	if adversaryNode, ok := node.(environments.AdversaryNode); ok {
+		adversary = adversaryNode.AdversaryToMove()
+	}
+
+	var best environments.Node
+	bestValue, total := 0.0, 0.0
+	for _, child := range children {
+		childValue := environments.CostOf(child) + a.chanceValue(e, child, depth+1)
+		total += childValue
+
+		better := childValue < bestValue
+		if adversary {
+			better = childValue > bestValue
+		}
+
+		if best == nil || better {
+			best = child
+			bestValue = childValue
+		}
+	}
+
+	if adversary && !a.minimax {
+		return best, total / float64(len(children))
+	}
+	return best, bestValue
+}
+
+// chanceValue returns the expected cost of the node's
+// outcomes, or the node's own value if it is deterministic
+func (a *expectimaxSearch) chanceValue(e environments.Environment, node environments.Node, depth int) float64 {
+	chanceNode, ok := node.(environments.ChanceNode)
+	if !ok {
+		return a.value(e, node, depth)
+	}
+
+	outcomes := chanceNode.Outcomes()
+	if len(outcomes) == 0 {
+		return a.value(e, node, depth)
+	}
+
+	expected := 0.0
+	for _, outcome := range outcomes {
+		if outcome.Probability == 0 {
+			continue
+		}
+		expected += outcome.Probability * (environments.CostOf(outcome.Node) + a.value(e, outcome.Node, depth))
+	}
+	return expected
+}
+
+// mostLikelyOutcome returns the outcome of the node with the
+// highest probability, if it is a chance node, or the first
+// of the most likely outcomes if several are as likely
+func mostLikelyOutcome(node environments.Node) (environments.Node, bool) {
+	chanceNode, ok := node.(environments.ChanceNode)
+	if !ok {
+		return nil, false
+	}
+
+	var best *environments.Outcome
+	for _, outcome := range chanceNode.Outcomes() {
+		outcome := outcome
+		if best == nil || outcome.Probability > best.Probability {
+			best = &outcome
+		}
+	}
+
+	if best == nil {
+		return nil, false
+	}
+	return best.Node, true
+}
@@ -0,0 +1,207 @@
+package algorithms
+
+import (
+	"container/heap"
+	"fmt"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+const defaultFocalEpsilon = 0.5
+
+// FocalSearch implements Focal Search (A*ε), which finds paths that
+// cost at most (1 + epsilon) times the cheapest path. Besides the
+// frontier ordered by cost and heuristic, it keeps the focal list
+// of the nodes whose cost and heuristic is within that bound of
+// the lowest, and expands the best of them by a secondary
+// heuristic. It can take the `epsilon` custom argument, which
+// defaults to 0.5, and the `secondary` custom argument, which is
+// either `heuristic` (the default) to prefer the node closest to
+// the goal, or `depth` to prefer the node with the most steps. See
+// https://doi.org/10.1109/TPAMI.1982.4767270
+type FocalSearch struct {
+	epsilon   float64
+	secondary string
+
+	// open contains the frontier ordered by cost and heuristic,
+	// which is split into focal, with the nodes within the bound
+	// ordered by the secondary heuristic, and pending, with the
+	// rest ordered by cost and heuristic
+	open    *PriorityNodeQueue
+	focal   *PriorityNodeQueue
+	pending *PriorityNodeQueue
+
+	cost              map[environments.NodeKey]float64
+	costWithHeuristic map[environments.NodeKey]float64
+	secondaryPriority map[environments.NodeKey]float64
+
+	// bound is the highest cost and heuristic
+	// of the nodes that can be in focal
+	bound float64
+
+	iterations int
+}
+
+// Run runs focal search on the environment and returns the result
+func (a FocalSearch) Run(ctx search.Context, e environments.Environment) (search.Result, error) {
+	if err := a.setParams(ctx.CustomSearchParams); err != nil {
+		return search.Result{}, err
+	}
+
+	a.setStart(e.Start())
+
+	node, err := a.findGoal(ctx, e)
+	if err != nil {
+		return search.Result{
+			Iterations:  a.iterations,
+			Environment: e,
+		}, err
+	}
+
+	return search.Result{
+		Node:        node,
+		Iterations:  a.iterations,
+		Environment: e,
+		CustomResultStats: map[string]string{
+			"f_bound": search.FormatCost(a.bound),
+		},
+	}, nil
+}
+
+func (a *FocalSearch) setParams(params search.CustomSearchParams) error {
+	var err error
+
+	if a.epsilon, err = floatParam(params, "epsilon", defaultFocalEpsilon); err != nil {
+		return err
+	}
+	if a.epsilon < 0 {
+		return fmt.Errorf("'epsilon' must be at least 0")
+	}
+
+	a.secondary = params["secondary"]
+	switch a.secondary {
+	case "":
+		a.secondary = "heuristic"
+	case "heuristic", "depth":
+	default:
+		return fmt.Errorf("'secondary' must be heuristic or depth, not %s", a.secondary)
+	}
+
+	return nil
+}
+
+// initialize the fields for this environment
+func (a *FocalSearch) setStart(start environments.Node) {
+	a.cost = make(map[environments.NodeKey]float64, 512)
+	a.cost[environments.KeyOf(start)] = 0
+
+	a.costWithHeuristic = make(map[environments.NodeKey]float64, 512)
+	a.costWithHeuristic[environments.KeyOf(start)] = environments.HeuristicOf(start)
+
+	a.secondaryPriority = make(map[environments.NodeKey]float64, 512)
+	a.secondaryPriority[environments.KeyOf(start)] = a.secondaryValue(start)
+
+	a.iterations = 0
+
+	a.open = NewPriorityNodeQueue(start, a.costWithHeuristic, PriorityNodeQueueConfig{})
+	a.focal = NewPriorityNodeQueue(start, a.secondaryPriority, PriorityNodeQueueConfig{
+		HigherIsBetter: a.secondary == "depth",
+	})
+	a.pending = &PriorityNodeQueue{
+		Frontier:    make([]environments.Node, 0, 512),
+		PriorityMap: a.costWithHeuristic,
+		NodeIndexes: make(map[environments.NodeKey]int, 512),
+	}
+	a.bound = a.boundFor(environments.HeuristicOf(start))
+}
+
+// secondaryValue returns the node's
+// priority in the focal list
+func (a *FocalSearch) secondaryValue(node environments.Node) float64 {
+	if a.secondary == "depth" {
+		return float64(len(node.Steps()))
+	}
+	return environments.HeuristicOf(node)
+}
+
+// boundFor returns the bound of the focal list
+// when the lowest cost and heuristic is fMin
+func (a *FocalSearch) boundFor(fMin float64) float64 {
+	return fMin * (1 + a.epsilon)
+}
+
+// find and return the goal node
+func (a *FocalSearch) findGoal(ctx search.Context, e environments.Environment) (environments.Node, error) {
+	for a.open.Len() > 0 {
+		if ctx.Cancelled() {
+			return nil, search.ErrCancelled
+		}
+
+		a.updateFocal()
+
+		currentNode := heap.Pop(a.focal).(environments.Node)
+
+		// the bound can drop with inconsistent heuristics,
+		// so nodes past it wait in pending until it rises
+		if a.costWithHeuristic[environments.KeyOf(currentNode)] > a.bound {
+			heap.Push(a.pending, currentNode)
+			continue
+		}
+
+		a.iterations++
+		heap.Remove(a.open, a.open.NodeIndexes[environments.KeyOf(currentNode)])
+
+		if e.IsGoalNode(currentNode) {
+			return currentNode, nil
+		}
+
+		currentNodeCost := a.cost[environments.KeyOf(currentNode)]
+		for _, child := range currentNode.Children() {
+			if err := environments.CheckCost(currentNode, child); err != nil {
+				return nil, err
+			}
+			childKey := environments.KeyOf(child)
+			childCost := currentNodeCost + environments.CostOf(child)
+			if previousChildCost, seen := a.cost[childKey]; seen && previousChildCost <= childCost {
+				continue
+			}
+
+			a.cost[childKey] = childCost
+			a.costWithHeuristic[childKey] = childCost + environments.HeuristicOf(child)
+			a.secondaryPriority[childKey] = a.secondaryValue(child)
+
+			replaceOrPush(a.open, child)
+			if _, inFocal := a.focal.NodeIndexes[childKey]; inFocal || a.costWithHeuristic[childKey] <= a.bound {
+				if pendingIdx, inPending := a.pending.NodeIndexes[childKey]; inPending {
+					heap.Remove(a.pending, pendingIdx)
+				}
+				replaceOrPush(a.focal, child)
+			} else {
+				replaceOrPush(a.pending, child)
+			}
+		}
+	}
+	return nil, fmt.Errorf("frontier is empty; searched entire space, but could not find goal state")
+}
+
+// updateFocal sets the bound from the lowest cost and heuristic
+// in open, and moves the pending nodes now within it to focal
+func (a *FocalSearch) updateFocal() {
+	a.bound = a.boundFor(a.costWithHeuristic[environments.KeyOf(a.open.Frontier[0])])
+
+	for a.pending.Len() > 0 && a.costWithHeuristic[environments.KeyOf(a.pending.Frontier[0])] <= a.bound {
+		heap.Push(a.focal, heap.Pop(a.pending))
+	}
+}
+
+// replaceOrPush replaces the node with the same key
+// in the queue, fixing its placement, or pushes it
+func replaceOrPush(queue *PriorityNodeQueue, node environments.Node) {
+	if currIdx, inQueue := queue.NodeIndexes[environments.KeyOf(node)]; inQueue {
+		queue.Frontier[currIdx] = node
+		heap.Fix(queue, currIdx)
+	} else {
+		heap.Push(queue, node)
+	}
+}
@@ -0,0 +1,127 @@
+package algorithms
+
+import (
+	"container/list"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+// FringeSearch implements Fringe Search, which finds the same
+// cheapest paths as A* but keeps its frontier in a list instead
+// of a priority queue. Like IDA*, it only expands nodes within
+// an f-limit, raising it to the lowest f over the limit once a
+// pass over the list is done, but it keeps the list and the
+// costs between passes rather than starting over. See
+// https://webdocs.cs.ualberta.ca/~holte/Publications/fringe.pdf
+type FringeSearch struct {
+	// fringe contains the nodes to visit in order,
+	// and elements finds a node's element in it
+	fringe   *list.List
+	elements map[environments.NodeKey]*list.Element
+
+	cost map[environments.NodeKey]float64
+
+	passes     int
+	iterations int
+}
+
+// Run runs Fringe Search on the environment and returns the result
+func (a FringeSearch) Run(ctx search.Context, e environments.Environment) (search.Result, error) {
+	a.setStart(e.Start())
+
+	node, err := a.findGoal(ctx, e)
+	if err != nil {
+		return search.Result{
+			Iterations:  a.iterations,
+			Environment: e,
+		}, err
+	}
+
+	return search.Result{
+		Node:        node,
+		Iterations:  a.iterations,
+		Environment: e,
+		CustomResultStats: map[string]string{
+			"passes": strconv.Itoa(a.passes),
+		},
+	}, nil
+}
+
+// initialize the fields for this environment
+func (a *FringeSearch) setStart(start environments.Node) {
+	a.fringe = list.New()
+	a.elements = make(map[environments.NodeKey]*list.Element, 512)
+	a.elements[environments.KeyOf(start)] = a.fringe.PushBack(start)
+
+	a.cost = make(map[environments.NodeKey]float64, 512)
+	a.cost[environments.KeyOf(start)] = 0
+
+	a.passes = 0
+	a.iterations = 0
+}
+
+// find and return the goal node
+func (a *FringeSearch) findGoal(ctx search.Context, e environments.Environment) (environments.Node, error) {
+	fLimit := environments.HeuristicOf(e.Start())
+
+	for a.fringe.Len() > 0 {
+		a.passes++
+
+		// fMin is the lowest f over the limit in this
+		// pass, or infinite if there weren't any
+		fMin := math.Inf(1)
+		for element := a.fringe.Front(); element != nil; {
+			if ctx.Cancelled() {
+				return nil, search.ErrCancelled
+			}
+
+			currentNode := element.Value.(environments.Node)
+			currentNodeCost := a.cost[environments.KeyOf(currentNode)]
+
+			if f := currentNodeCost + environments.HeuristicOf(currentNode); f > fLimit {
+				fMin = math.Min(fMin, f)
+				element = element.Next()
+				continue
+			}
+
+			a.iterations++
+			if e.IsGoalNode(currentNode) {
+				return currentNode, nil
+			}
+
+			// children are visited later in this
+			// same pass, right after this node
+			for _, child := range currentNode.Children() {
+				if err := environments.CheckCost(currentNode, child); err != nil {
+					return nil, err
+				}
+				childKey := environments.KeyOf(child)
+				childCost := currentNodeCost + environments.CostOf(child)
+				if previousChildCost, seen := a.cost[childKey]; seen && previousChildCost <= childCost {
+					continue
+				}
+				a.cost[childKey] = childCost
+
+				if previous, inFringe := a.elements[childKey]; inFringe {
+					a.fringe.Remove(previous)
+				}
+				a.elements[childKey] = a.fringe.InsertAfter(child, element)
+			}
+
+			next := element.Next()
+			a.fringe.Remove(element)
+			delete(a.elements, environments.KeyOf(currentNode))
+			element = next
+		}
+
+		if math.IsInf(fMin, 1) {
+			break
+		}
+		fLimit = fMin
+	}
+	return nil, fmt.Errorf("fringe is empty; searched entire space, but could not find goal state")
+}
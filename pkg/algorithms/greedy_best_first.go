@@ -9,20 +9,25 @@ import (
 )
 
 // GreedyBestFirst implements the greedy best first
-// search algorithm
+// search algorithm. It can take the `tie_break`
+// and `tie_break_seed` custom arguments (see TieBreak)
 type GreedyBestFirst struct {
 	queue *PriorityNodeQueue
 
-	heuristic map[string]int
+	heuristic map[environments.NodeKey]float64
 
 	iterations int
 }
 
 // Run runs A* on the environment and returns the result
 func (a GreedyBestFirst) Run(ctx search.Context, e environments.Environment) (search.Result, error) {
-	a.setStart(e.Start())
+	config, err := tieBreakConfig(ctx.CustomSearchParams, PriorityNodeQueueConfig{})
+	if err != nil {
+		return search.Result{}, err
+	}
+	a.setStart(e.Start(), config)
 
-	node, err := a.findGoal(e)
+	node, err := a.findGoal(ctx, e)
 	if err != nil {
 		return search.Result{}, err
 	}
@@ -35,21 +40,24 @@ func (a GreedyBestFirst) Run(ctx search.Context, e environments.Environment) (se
 }
 
 // initialize GreedyBestFirst's fields for this environment
-func (a *GreedyBestFirst) setStart(start environments.Node) {
+func (a *GreedyBestFirst) setStart(start environments.Node, config PriorityNodeQueueConfig) {
 
-	a.heuristic = make(map[string]int, 512)
-	a.heuristic[start.Name()] = start.Heuristic()
+	a.heuristic = make(map[environments.NodeKey]float64, 512)
+	a.heuristic[environments.KeyOf(start)] = environments.HeuristicOf(start)
 
 	a.iterations = 0
 
-	a.queue = NewPriorityNodeQueue(start, a.heuristic, PriorityNodeQueueConfig{})
+	a.queue = NewPriorityNodeQueue(start, a.heuristic, config)
 }
 
 // find and return the goal node
-func (a *GreedyBestFirst) findGoal(e environments.Environment) (environments.Node, error) {
+func (a *GreedyBestFirst) findGoal(ctx search.Context, e environments.Environment) (environments.Node, error) {
 	// if nothing in queue/frontier, then it is impossible
 	// to find the goal node
 	for a.queue.Len() > 0 {
+		if ctx.Cancelled() {
+			return nil, search.ErrCancelled
+		}
 		a.iterations++
 		currentNode := heap.Pop(a.queue).(environments.Node)
 
@@ -58,11 +66,12 @@ func (a *GreedyBestFirst) findGoal(e environments.Environment) (environments.Nod
 		}
 
 		for _, child := range currentNode.Children() {
-			_, seen := a.heuristic[child.Name()]
+			childKey := environments.KeyOf(child)
+			_, seen := a.heuristic[childKey]
 
 			// new node, add its heuristic
 			if !seen {
-				a.heuristic[child.Name()] = child.Heuristic()
+				a.heuristic[childKey] = environments.HeuristicOf(child)
 				heap.Push(a.queue, child)
 			}
 		}
@@ -0,0 +1,85 @@
+package algorithms
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/hpa"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+const defaultClusterSize = 10
+
+// HierarchicalAStar implements HPA*, which searches an
+// abstraction of a grid (see the hpa package) and refines
+// the result into a path on the grid. It can take the
+// `abstraction` custom argument, the path of an abstraction
+// saved with `go-search abstract`, or otherwise builds the
+// abstraction with the `cluster_size` custom argument
+type HierarchicalAStar struct {
+	abstractionPath string
+	clusterSize     int
+}
+
+// Run runs HPA* on the environment and returns the result
+func (a HierarchicalAStar) Run(ctx search.Context, e environments.Environment) (search.Result, error) {
+	if err := a.setParams(ctx.CustomSearchParams); err != nil {
+		return search.Result{}, err
+	}
+
+	grid, ok := e.(*environments.GridEnvironment)
+	if !ok {
+		return search.Result{}, fmt.Errorf("environment %s is not a grid", e.Name())
+	}
+
+	abstraction, err := a.getAbstraction(ctx, grid)
+	if err != nil {
+		return search.Result{}, err
+	}
+
+	path, err := abstraction.FindPath(ctx, grid, grid.StartPoint(), grid.EndPoint())
+	if err != nil {
+		return search.Result{}, err
+	}
+
+	node, err := grid.PathThrough(path.Points)
+	if err != nil {
+		return search.Result{}, err
+	}
+
+	return search.Result{
+		Node:        node,
+		Iterations:  path.Expanded,
+		Environment: e,
+		CustomResultStats: map[string]string{
+			"abstract_nodes":       strconv.Itoa(len(abstraction.Nodes)),
+			"abstract_path_length": strconv.Itoa(path.AbstractLength),
+		},
+	}, nil
+}
+
+func (a *HierarchicalAStar) setParams(params search.CustomSearchParams) error {
+	a.abstractionPath = params["abstraction"]
+
+	var err error
+	a.clusterSize, err = intParam(params, "cluster_size", defaultClusterSize)
+	return err
+}
+
+// getAbstraction loads the abstraction if a path was
+// provided, and otherwise builds it
+func (a *HierarchicalAStar) getAbstraction(ctx search.Context, grid *environments.GridEnvironment) (*hpa.Abstraction, error) {
+	if a.abstractionPath == "" {
+		return hpa.Build(ctx, grid, a.clusterSize)
+	}
+
+	f, err := os.Open(a.abstractionPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not open abstraction: %w", err)
+	}
+	defer f.Close()
+
+	return hpa.Load(f)
+}
@@ -1,6 +1,7 @@
 package algorithms
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 
@@ -26,7 +27,7 @@ func (a IterativeDeepening) Run(ctx search.Context, e environments.Environment)
 		return search.Result{}, err
 	}
 
-	return a.getResult(e)
+	return a.getResult(ctx, e)
 }
 
 func (a *IterativeDeepening) setParams(params search.CustomSearchParams) error {
@@ -58,7 +59,7 @@ func (a *IterativeDeepening) setParams(params search.CustomSearchParams) error {
 }
 
 // find and return the goal node
-func (a *IterativeDeepening) getResult(e environments.Environment) (search.Result, error) {
+func (a *IterativeDeepening) getResult(ctx search.Context, e environments.Environment) (search.Result, error) {
 	currDepth := a.initialDepth
 	for {
 		if a.maxDepth != -1 && currDepth >= a.maxDepth {
@@ -70,12 +71,16 @@ func (a *IterativeDeepening) getResult(e environments.Environment) (search.Resul
 			CustomSearchParams: search.CustomSearchParams{
 				"depth_limit": strconv.Itoa(currDepth),
 			},
+			Done: ctx.Done,
 		}
 		result, err := depthLimited.Run(depthLimitedCtx, e)
 		if err == nil {
 			result.Iterations = a.iterations + result.Iterations
 			return result, nil
 		}
+		if errors.Is(err, search.ErrCancelled) {
+			return search.Result{}, err
+		}
 
 		a.iterations = a.iterations + result.Iterations
 
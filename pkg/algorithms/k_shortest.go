@@ -0,0 +1,324 @@
+package algorithms
+
+import (
+	"container/heap"
+	"fmt"
+	"strings"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+const (
+	defaultKShortestPaths = 3
+
+	// defaultMaxPathsFactor is multiplied by k to get the
+	// default number of paths generated before giving up
+	// on finding dissimilar enough paths
+	defaultMaxPathsFactor = 10
+)
+
+// KShortestPaths implements Yen's algorithm, which finds the
+// `k` cheapest loopless paths from the start to the goal. Each
+// path after the first deviates from a previous path at some
+// node, so it searches from every node of the previous path
+// with the edges taken by the paths found so far removed. If
+// `max_similarity` (0 to 1) is set, paths that share more than
+// that fraction of their steps with a cheaper path that was
+// returned are skipped, and at most `max_paths` paths are
+// generated while looking for dissimilar ones. See
+// https://en.wikipedia.org/wiki/Yen%27s_algorithm
+type KShortestPaths struct {
+	k             int
+	maxSimilarity float64
+	maxPaths      int
+
+	// blockedNodes and blockedEdges are skipped
+	// while searching from a deviation
+	blockedNodes map[environments.NodeKey]bool
+	blockedEdges map[[2]environments.NodeKey]bool
+
+	iterations int
+}
+
+// Run runs Yen's algorithm on the environment and returns the
+// cheapest path, with the others as alternatives
+func (a KShortestPaths) Run(ctx search.Context, e environments.Environment) (search.Result, error) {
+	if err := a.setParams(ctx.CustomSearchParams); err != nil {
+		return search.Result{}, err
+	}
+
+	paths, err := a.findPaths(ctx, e)
+	if err != nil {
+		return search.Result{
+			Iterations:  a.iterations,
+			Environment: e,
+		}, err
+	}
+
+	costs := make([]string, len(paths))
+	for i, path := range paths {
+		costs[i] = search.FormatCost(search.Result{Node: path}.TotalCost())
+	}
+
+	return search.Result{
+		Node:         paths[0],
+		Alternatives: paths[1:],
+		Iterations:   a.iterations,
+		Environment:  e,
+		CustomResultStats: map[string]string{
+			"path_costs": strings.Join(costs, ", "),
+		},
+	}, nil
+}
+
+func (a *KShortestPaths) setParams(params search.CustomSearchParams) error {
+	var err error
+
+	if a.k, err = intParam(params, "k", defaultKShortestPaths); err != nil {
+		return err
+	}
+	if a.k < 1 {
+		return fmt.Errorf("'k' must be at least 1")
+	}
+
+	if a.maxSimilarity, err = floatParam(params, "max_similarity", 1); err != nil {
+		return err
+	}
+	if a.maxSimilarity < 0 || a.maxSimilarity > 1 {
+		return fmt.Errorf("'max_similarity' must be between 0 and 1")
+	}
+
+	if a.maxPaths, err = intParam(params, "max_paths", a.k*defaultMaxPathsFactor); err != nil {
+		return err
+	}
+	if a.maxPaths < a.k {
+		return fmt.Errorf("'max_paths' must be at least 'k'")
+	}
+
+	return nil
+}
+
+// findPaths returns up to k paths, cheapest first
+func (a *KShortestPaths) findPaths(ctx search.Context, e environments.Environment) ([]environments.Node, error) {
+	a.iterations = 0
+
+	a.blockedNodes = map[environments.NodeKey]bool{}
+	a.blockedEdges = map[[2]environments.NodeKey]bool{}
+	first, err := a.searchFrom(ctx, e, e.Start())
+	if err != nil {
+		return nil, err
+	}
+	if ctx.Cancelled() {
+		return nil, search.ErrCancelled
+	}
+	if first == nil {
+		return nil, fmt.Errorf("frontier is empty; searched entire space, but could not find goal state")
+	}
+
+	// generated contains every path found, which are all
+	// deviated from, while out only contains the paths that
+	// are dissimilar enough to be returned
+	generated := [][]environments.Node{pathTo(first)}
+	out := []environments.Node{first}
+
+	candidates := &pathQueue{}
+	seen := map[string]bool{pathKey(generated[0]): true}
+
+	for len(out) < a.k && len(generated) < a.maxPaths {
+		previous := generated[len(generated)-1]
+
+		for i := 0; i < len(previous)-1; i++ {
+			a.blockDeviation(generated, previous, i)
+
+			goal, err := a.searchFrom(ctx, e, previous[i])
+			if err != nil {
+				return nil, err
+			}
+			if ctx.Cancelled() {
+				return nil, search.ErrCancelled
+			}
+			if goal == nil {
+				continue
+			}
+
+			path := pathTo(goal)
+			if key := pathKey(path); !seen[key] {
+				seen[key] = true
+				heap.Push(candidates, candidate{path: path, cost: search.Result{Node: goal}.TotalCost()})
+			}
+		}
+
+		if candidates.Len() == 0 {
+			break
+		}
+
+		path := heap.Pop(candidates).(candidate).path
+		generated = append(generated, path)
+		if a.dissimilar(path, out) {
+			out = append(out, path[len(path)-1])
+		}
+	}
+
+	return out, nil
+}
+
+// blockDeviation blocks the edges that the generated paths
+// take from the first i+1 nodes of the path, if they share
+// them, and the nodes before the ith node, so searching from
+// the ith node finds a new loopless path
+func (a *KShortestPaths) blockDeviation(generated [][]environments.Node, path []environments.Node, i int) {
+	a.blockedNodes = make(map[environments.NodeKey]bool, i)
+	for _, node := range path[:i] {
+		a.blockedNodes[environments.KeyOf(node)] = true
+	}
+
+	a.blockedEdges = make(map[[2]environments.NodeKey]bool, len(generated))
+	for _, other := range generated {
+		if len(other) > i+1 && sharesPrefix(path, other, i+1) {
+			a.blockedEdges[[2]environments.NodeKey{environments.KeyOf(other[i]), environments.KeyOf(other[i+1])}] = true
+		}
+	}
+}
+
+// searchFrom runs A* from the node without the blocked nodes
+// and edges, and returns the goal node, or nil if it can't be
+// reached or the search was cancelled. The node's parents are
+// kept, so the goal's path goes through them
+func (a *KShortestPaths) searchFrom(ctx search.Context, e environments.Environment, start environments.Node) (environments.Node, error) {
+	cost := map[environments.NodeKey]float64{environments.KeyOf(start): 0}
+	costWithHeuristic := map[environments.NodeKey]float64{environments.KeyOf(start): environments.HeuristicOf(start)}
+	queue := NewPriorityNodeQueue(start, costWithHeuristic, PriorityNodeQueueConfig{})
+
+	for queue.Len() > 0 && !ctx.Cancelled() {
+		a.iterations++
+		currentNode := heap.Pop(queue).(environments.Node)
+
+		if e.IsGoalNode(currentNode) {
+			return currentNode, nil
+		}
+
+		currentNodeCost := cost[environments.KeyOf(currentNode)]
+		for _, child := range currentNode.Children() {
+			if err := environments.CheckCost(currentNode, child); err != nil {
+				return nil, err
+			}
+			childKey := environments.KeyOf(child)
+			if a.blockedNodes[childKey] || a.blockedEdges[[2]environments.NodeKey{environments.KeyOf(currentNode), childKey}] {
+				continue
+			}
+
+			childCost := currentNodeCost + environments.CostOf(child)
+			if previousChildCost, seen := cost[childKey]; seen && previousChildCost <= childCost {
+				continue
+			}
+
+			cost[childKey] = childCost
+			costWithHeuristic[childKey] = childCost + environments.HeuristicOf(child)
+			if currIdx, inQueue := queue.NodeIndexes[childKey]; inQueue {
+				queue.Frontier[currIdx] = child
+				heap.Fix(queue, currIdx)
+			} else {
+				heap.Push(queue, child)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// dissimilar checks that the path doesn't share more than
+// the maximum similarity of its steps with any of the others
+func (a *KShortestPaths) dissimilar(path []environments.Node, others []environments.Node) bool {
+	if a.maxSimilarity >= 1 {
+		return true
+	}
+
+	edges := pathEdges(path)
+	for _, other := range others {
+		shared := 0
+		for edge := range pathEdges(pathTo(other)) {
+			if edges[edge] {
+				shared++
+			}
+		}
+
+		if len(edges) > 0 && float64(shared)/float64(len(edges)) > a.maxSimilarity {
+			return false
+		}
+	}
+	return true
+}
+
+// pathTo returns the nodes from the start to the node
+func pathTo(node environments.Node) []environments.Node {
+	path := make([]environments.Node, 0, 64)
+	for parent := node; parent != nil; parent = parent.Parent() {
+		path = append(path, parent)
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// pathKey returns a string identifying the path
+func pathKey(path []environments.Node) string {
+	names := make([]string, len(path))
+	for i, node := range path {
+		names[i] = node.Name()
+	}
+	return strings.Join(names, "\x00")
+}
+
+// pathEdges returns the set of steps in the path
+func pathEdges(path []environments.Node) map[[2]environments.NodeKey]bool {
+	edges := make(map[[2]environments.NodeKey]bool, len(path))
+	for i := 1; i < len(path); i++ {
+		edges[[2]environments.NodeKey{environments.KeyOf(path[i-1]), environments.KeyOf(path[i])}] = true
+	}
+	return edges
+}
+
+// sharesPrefix checks if the first n nodes of both paths are the same
+func sharesPrefix(path, other []environments.Node, n int) bool {
+	if len(other) < n {
+		return false
+	}
+	for i := 0; i < n; i++ {
+		if environments.KeyOf(path[i]) != environments.KeyOf(other[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// candidate is a path that could be the next shortest
+type candidate struct {
+	path []environments.Node
+	cost float64
+}
+
+// pathQueue orders candidates by cost, and then by length
+type pathQueue []candidate
+
+func (q pathQueue) Len() int { return len(q) }
+
+func (q pathQueue) Less(i, j int) bool {
+	if q[i].cost != q[j].cost {
+		return q[i].cost < q[j].cost
+	}
+	return len(q[i].path) < len(q[j].path)
+}
+
+func (q pathQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *pathQueue) Push(x interface{}) { *q = append(*q, x.(candidate)) }
+
+func (q *pathQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
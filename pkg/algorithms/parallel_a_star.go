@@ -0,0 +1,297 @@
+package algorithms
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+// ParallelAStar implements Hash Distributed A* (HDA*), which runs A*
+// on several worker goroutines. Every node is owned by one worker,
+// picked by hashing its key, which keeps its cost and expands it;
+// the children a worker generates are sent to their owners. Once
+// a goal is found, workers keep expanding the nodes that could
+// lead to a cheaper one, and the search ends when there are none
+// left on any worker and no children waiting to be received, so
+// the result is as cheap as A*'s. It can take the `workers`
+// custom argument, which defaults to the number of CPUs. See
+// https://doi.org/10.1609/icaps.v19i1.13366
+type ParallelAStar struct{}
+
+// hdaSearch contains the state shared
+// between the workers of a search
+type hdaSearch struct {
+	ctx     search.Context
+	env     environments.Environment
+	workers []*hdaWorker
+
+	// pending is the number of nodes sent to or queued
+	// on any worker that haven't been expanded or
+	// discarded yet, so the search ends when it is 0
+	pending int64
+	done    chan struct{}
+	once    sync.Once
+
+	// err is the error that ended the search early
+	err error
+
+	// bestCost is the bits of the cost of the cheapest
+	// goal found so far, for workers to prune against
+	bestCost uint64
+	bestMu   sync.Mutex
+	best     environments.Node
+}
+
+// Run runs HDA* on the environment and returns the result
+func (a ParallelAStar) Run(ctx search.Context, e environments.Environment) (search.Result, error) {
+	workers, err := intParam(ctx.CustomSearchParams, "workers", runtime.NumCPU())
+	if err != nil {
+		return search.Result{}, err
+	}
+	if workers < 1 {
+		return search.Result{}, fmt.Errorf("'workers' must be at least 1")
+	}
+
+	s := &hdaSearch{
+		ctx:      ctx,
+		env:      e,
+		done:     make(chan struct{}),
+		bestCost: math.Float64bits(math.Inf(1)),
+		workers:  make([]*hdaWorker, workers),
+	}
+	for i := range s.workers {
+		s.workers[i] = newHDAWorker()
+	}
+
+	start := e.Start()
+	s.send(start, 0)
+
+	var wg sync.WaitGroup
+	for _, w := range s.workers {
+		wg.Add(1)
+		go func(w *hdaWorker) {
+			defer wg.Done()
+			s.work(w)
+		}(w)
+	}
+	wg.Wait()
+
+	if ctx.Cancelled() {
+		return search.Result{}, search.ErrCancelled
+	}
+	if s.err != nil {
+		return search.Result{}, s.err
+	}
+
+	iterations := 0
+	expansions := make([]string, len(s.workers))
+	for i, w := range s.workers {
+		iterations += w.expansions
+		expansions[i] = strconv.Itoa(w.expansions)
+	}
+
+	if s.best == nil {
+		return search.Result{
+			Iterations:  iterations,
+			Environment: e,
+		}, fmt.Errorf("frontier is empty; searched entire space, but could not find goal state")
+	}
+
+	return search.Result{
+		Node:        s.best,
+		Iterations:  iterations,
+		Environment: e,
+		CustomResultStats: map[string]string{
+			"worker_expansions": strings.Join(expansions, ", "),
+		},
+	}, nil
+}
+
+// hdaMessage is a node sent to its owner, with
+// the cost of the path it was reached by
+type hdaMessage struct {
+	node environments.Node
+	cost float64
+}
+
+// hdaWorker contains the nodes owned by one worker
+type hdaWorker struct {
+	queue             *PriorityNodeQueue
+	cost              map[environments.NodeKey]float64
+	costWithHeuristic map[environments.NodeKey]float64
+
+	// inbox contains the nodes sent to this worker,
+	// and notify is signalled when it's added to
+	inboxMu sync.Mutex
+	inbox   []hdaMessage
+	notify  chan struct{}
+
+	expansions int
+}
+
+func newHDAWorker() *hdaWorker {
+	w := &hdaWorker{
+		cost:              make(map[environments.NodeKey]float64, 512),
+		costWithHeuristic: make(map[environments.NodeKey]float64, 512),
+		notify:            make(chan struct{}, 1),
+	}
+	w.queue = &PriorityNodeQueue{
+		Frontier:    make([]environments.Node, 0, 512),
+		PriorityMap: w.costWithHeuristic,
+		NodeIndexes: make(map[environments.NodeKey]int, 512),
+	}
+	return w
+}
+
+// work receives and expands nodes until
+// the search is done or cancelled
+func (s *hdaSearch) work(w *hdaWorker) {
+	for !s.stopped() {
+		w.inboxMu.Lock()
+		inbox := w.inbox
+		w.inbox = nil
+		w.inboxMu.Unlock()
+
+		for _, message := range inbox {
+			s.receive(w, message)
+		}
+
+		if w.queue.Len() == 0 {
+			select {
+			case <-w.notify:
+				continue
+			case <-s.done:
+				return
+			case <-s.ctx.Done:
+				return
+			}
+		}
+
+		s.expand(w, heap.Pop(w.queue).(environments.Node))
+	}
+}
+
+// receive adds the node to the worker's frontier,
+// unless it was already reached more cheaply or it
+// can't lead to a cheaper goal than the best so far
+func (s *hdaSearch) receive(w *hdaWorker, message hdaMessage) {
+	key := environments.KeyOf(message.node)
+	if previousCost, seen := w.cost[key]; seen && previousCost <= message.cost {
+		s.finish()
+		return
+	}
+
+	costWithHeuristic := message.cost + environments.HeuristicOf(message.node)
+	if costWithHeuristic >= s.loadBestCost() {
+		s.finish()
+		return
+	}
+
+	w.cost[key] = message.cost
+	w.costWithHeuristic[key] = costWithHeuristic
+	if currIdx, inQueue := w.queue.NodeIndexes[key]; inQueue {
+		// replacing the node leaves one less
+		// node for the search to finish
+		w.queue.Frontier[currIdx] = message.node
+		heap.Fix(w.queue, currIdx)
+		s.finish()
+	} else {
+		heap.Push(w.queue, message.node)
+	}
+}
+
+// expand sends the children of the node to their owners,
+// or records the node if it is a goal
+func (s *hdaSearch) expand(w *hdaWorker, node environments.Node) {
+	defer s.finish()
+
+	// the best cost may have dropped since the node
+	// was queued, so it may no longer be worth expanding
+	if w.costWithHeuristic[environments.KeyOf(node)] >= s.loadBestCost() {
+		return
+	}
+	w.expansions++
+
+	cost := w.cost[environments.KeyOf(node)]
+	if s.env.IsGoalNode(node) {
+		s.offer(node, cost)
+		return
+	}
+
+	for _, child := range node.Children() {
+		if err := environments.CheckCost(node, child); err != nil {
+			s.fail(err)
+			return
+		}
+		s.send(child, cost+environments.CostOf(child))
+	}
+}
+
+// send adds the node to its owner's inbox
+func (s *hdaSearch) send(node environments.Node, cost float64) {
+	atomic.AddInt64(&s.pending, 1)
+
+	w := s.workers[environments.KeyOf(node).Hash()%uint64(len(s.workers))]
+
+	w.inboxMu.Lock()
+	w.inbox = append(w.inbox, hdaMessage{node: node, cost: cost})
+	w.inboxMu.Unlock()
+
+	select {
+	case w.notify <- struct{}{}:
+	default: // already notified
+	}
+}
+
+// finish marks a node as expanded or discarded,
+// ending the search if it was the last one
+func (s *hdaSearch) finish() {
+	if atomic.AddInt64(&s.pending, -1) == 0 {
+		s.once.Do(func() { close(s.done) })
+	}
+}
+
+// fail ends the search early with the error
+func (s *hdaSearch) fail(err error) {
+	s.once.Do(func() {
+		s.err = err
+		close(s.done)
+	})
+}
+
+// stopped checks if the search is
+// done, failed or cancelled
+func (s *hdaSearch) stopped() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return s.ctx.Cancelled()
+	}
+}
+
+// offer records the goal node if it is
+// cheaper than the best one so far
+func (s *hdaSearch) offer(node environments.Node, cost float64) {
+	s.bestMu.Lock()
+	defer s.bestMu.Unlock()
+
+	if cost < s.loadBestCost() {
+		s.best = node
+		atomic.StoreUint64(&s.bestCost, math.Float64bits(cost))
+	}
+}
+
+// loadBestCost returns the cost of the
+// cheapest goal found so far
+func (s *hdaSearch) loadBestCost() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&s.bestCost))
+}
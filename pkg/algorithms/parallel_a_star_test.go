@@ -0,0 +1,74 @@
+package algorithms
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+// referenceCosts are the costs of the shortest
+// paths through some of the premade environments
+var referenceCosts = map[string]float64{
+	"bucharest": 418,
+	"corners":   39,
+	"maze":      61,
+}
+
+// slowEnvironments are skipped with -short, as naming their
+// states takes minutes with -race
+var slowEnvironments = map[string]bool{
+	"warehouse": true,
+}
+
+// TestParallelAStarMatchesAStar checks that HDA* finds paths as cheap as
+// A*'s on every premade environment, with any number of workers. Run it
+// with -race, as the workers share the best cost and the pending count
+func TestParallelAStarMatchesAStar(t *testing.T) {
+	names := environments.PremadeEnvironments()
+	sort.Strings(names)
+
+	for _, name := range names {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			if testing.Short() && slowEnvironments[name] {
+				t.Skip("slow environment")
+			}
+
+			env, err := environments.GetEnvironment(name)
+			if err != nil {
+				t.Fatalf("could not get environment: %s", err)
+			}
+			if err := env.Validate(); err != nil {
+				t.Fatalf("invalid environment: %s", err)
+			}
+
+			want, err := AStar{}.Run(search.Context{}, env)
+			if err != nil {
+				t.Fatalf("a* failed: %s", err)
+			}
+			if reference, ok := referenceCosts[name]; ok && want.TotalCost() != reference {
+				t.Fatalf("a* found cost %s, expected %s", search.FormatCost(want.TotalCost()), search.FormatCost(reference))
+			}
+
+			for _, workers := range []int{1, 2, 8} {
+				got, err := ParallelAStar{}.Run(search.Context{
+					CustomSearchParams: search.CustomSearchParams{"workers": strconv.Itoa(workers)},
+				}, env)
+				if err != nil {
+					t.Fatalf("parallel_a* with %d workers failed: %s", workers, err)
+				}
+
+				if !env.IsGoalNode(got.Node) {
+					t.Errorf("parallel_a* with %d workers ended on %s, which isn't a goal", workers, got.Node.Name())
+				}
+				if math.Abs(got.TotalCost()-want.TotalCost()) > 1e-9 {
+					t.Errorf("parallel_a* with %d workers found cost %s, but a* found %s", workers, search.FormatCost(got.TotalCost()), search.FormatCost(want.TotalCost()))
+				}
+			}
+		})
+	}
+}
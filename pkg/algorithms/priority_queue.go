@@ -2,24 +2,92 @@ package algorithms
 
 import (
 	"container/heap"
+	"fmt"
+	"math/rand"
 
 	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
 )
 
+// TieBreak picks which of two nodes with
+// the same priority is popped first
+type TieBreak int
+
+const (
+	// TieBreakNone leaves ties to the heap's order
+	TieBreakNone TieBreak = iota
+	// TieBreakHigherCost prefers the node with
+	// the higher cost so far (g), which is
+	// usually closer to the goal
+	TieBreakHigherCost
+	// TieBreakLowerHeuristic prefers the node
+	// with the lower heuristic (h)
+	TieBreakLowerHeuristic
+	// TieBreakLIFO prefers the node pushed last
+	TieBreakLIFO
+	// TieBreakFIFO prefers the node pushed first
+	TieBreakFIFO
+	// TieBreakRandom prefers a random node,
+	// which is the same for the same Seed
+	TieBreakRandom
+)
+
+// tieBreaks are the names of the
+// tie breaks for the `tie_break` param
+var tieBreaks = map[string]TieBreak{
+	"none":     TieBreakNone,
+	"higher_g": TieBreakHigherCost,
+	"lower_h":  TieBreakLowerHeuristic,
+	"lifo":     TieBreakLIFO,
+	"fifo":     TieBreakFIFO,
+	"random":   TieBreakRandom,
+}
+
 // PriorityNodeQueueConfig defines optional
 // arguments when creating a priority node queue
 type PriorityNodeQueueConfig struct {
 	HigherIsBetter bool
+
+	// TieBreak picks between nodes with the same priority
+	TieBreak TieBreak
+
+	// CostMap is referenced to get the cost so far of a
+	// node for TieBreakHigherCost. If it's nil, the cost
+	// is found by following the node's parents
+	CostMap map[environments.NodeKey]float64
+
+	// Seed seeds TieBreakRandom
+	Seed int64
+}
+
+// tieBreakConfig sets the tie break of the config from
+// the `tie_break` and `tie_break_seed` custom arguments
+func tieBreakConfig(params search.CustomSearchParams, config PriorityNodeQueueConfig) (PriorityNodeQueueConfig, error) {
+	if name, ok := params["tie_break"]; ok {
+		tieBreak, ok := tieBreaks[name]
+		if !ok {
+			return config, fmt.Errorf("unknown 'tie_break' %s, must be none, higher_g, lower_h, lifo, fifo or random", name)
+		}
+		config.TieBreak = tieBreak
+	}
+
+	seed, err := intParam(params, "tie_break_seed", 1)
+	if err != nil {
+		return config, err
+	}
+	config.Seed = int64(seed)
+
+	return config, nil
 }
 
 // NewPriorityNodeQueue initializes a priority queue with the provided
 // start node and priority map to use
-func NewPriorityNodeQueue(start environments.Node, priorityMap map[string]int, config PriorityNodeQueueConfig) *PriorityNodeQueue {
+func NewPriorityNodeQueue(start environments.Node, priorityMap map[environments.NodeKey]float64, config PriorityNodeQueueConfig) *PriorityNodeQueue {
 	frontier := make([]environments.Node, 1, 512)
 	frontier[0] = start
 
-	nodeIndexes := make(map[string]int, 512)
-	nodeIndexes[start.Name()] = 0
+	nodeIndexes := make(map[environments.NodeKey]int, 512)
+	nodeIndexes[environments.KeyOf(start)] = 0
 
 	queue := &PriorityNodeQueue{
 		NodeIndexes:             nodeIndexes,
@@ -27,6 +95,7 @@ func NewPriorityNodeQueue(start environments.Node, priorityMap map[string]int, c
 		PriorityMap:             priorityMap,
 		PriorityNodeQueueConfig: config,
 	}
+	queue.addOrder(start)
 
 	heap.Init(queue)
 
@@ -49,12 +118,18 @@ type PriorityNodeQueue struct {
 	// for a node. Lowest priority
 	// nodes are the ones that are
 	// popped
-	PriorityMap map[string]int
+	PriorityMap map[environments.NodeKey]float64
 
 	// NodeIndexes keeps track
 	// of the indexes of nodes
-	// based upon their name
-	NodeIndexes map[string]int
+	// by their keys (see environments.KeyOf)
+	NodeIndexes map[environments.NodeKey]int
+
+	// order is the order each node was pushed in, or
+	// its random order, for the tie breaks that use it
+	order     map[environments.NodeKey]int64
+	nextOrder int64
+	random    *rand.Rand
 }
 
 func (q *PriorityNodeQueue) Len() int {
@@ -62,25 +137,81 @@ func (q *PriorityNodeQueue) Len() int {
 }
 
 func (q *PriorityNodeQueue) Less(i, j int) bool {
-	if q.HigherIsBetter {
-		return q.PriorityMap[q.Frontier[i].Name()] > q.PriorityMap[q.Frontier[j].Name()]
+	iPriority, jPriority := q.PriorityMap[environments.KeyOf(q.Frontier[i])], q.PriorityMap[environments.KeyOf(q.Frontier[j])]
+	if iPriority != jPriority {
+		if q.HigherIsBetter {
+			return iPriority > jPriority
+		}
+		return iPriority < jPriority
 	}
-	return q.PriorityMap[q.Frontier[i].Name()] < q.PriorityMap[q.Frontier[j].Name()]
+	return q.breakTie(q.Frontier[i], q.Frontier[j])
+}
+
+// breakTie checks if a should be popped before b
+// when they have the same priority
+func (q *PriorityNodeQueue) breakTie(a, b environments.Node) bool {
+	switch q.TieBreak {
+	case TieBreakHigherCost:
+		return q.costOf(a) > q.costOf(b)
+	case TieBreakLowerHeuristic:
+		return environments.HeuristicOf(a) < environments.HeuristicOf(b)
+	case TieBreakLIFO:
+		return q.order[environments.KeyOf(a)] > q.order[environments.KeyOf(b)]
+	case TieBreakFIFO, TieBreakRandom:
+		return q.order[environments.KeyOf(a)] < q.order[environments.KeyOf(b)]
+	}
+	return false
+}
+
+// costOf returns the cost so far of the node
+func (q *PriorityNodeQueue) costOf(node environments.Node) float64 {
+	if q.CostMap != nil {
+		return q.CostMap[environments.KeyOf(node)]
+	}
+
+	cost := 0.0
+	for parent := node; parent != nil; parent = parent.Parent() {
+		cost += environments.CostOf(parent)
+	}
+	return cost
+}
+
+// addOrder records the order of the
+// node if the tie break uses it
+func (q *PriorityNodeQueue) addOrder(node environments.Node) {
+	switch q.TieBreak {
+	case TieBreakLIFO, TieBreakFIFO:
+		q.nextOrder++
+		q.setOrder(node, q.nextOrder)
+	case TieBreakRandom:
+		if q.random == nil {
+			q.random = rand.New(rand.NewSource(q.Seed))
+		}
+		q.setOrder(node, q.random.Int63())
+	}
+}
+
+func (q *PriorityNodeQueue) setOrder(node environments.Node, order int64) {
+	if q.order == nil {
+		q.order = make(map[environments.NodeKey]int64, 512)
+	}
+	q.order[environments.KeyOf(node)] = order
 }
 
 func (q *PriorityNodeQueue) Swap(i, j int) {
 
 	q.Frontier[i], q.Frontier[j] = q.Frontier[j], q.Frontier[i]
 
-	q.NodeIndexes[q.Frontier[i].Name()] = i
-	q.NodeIndexes[q.Frontier[j].Name()] = j
+	q.NodeIndexes[environments.KeyOf(q.Frontier[i])] = i
+	q.NodeIndexes[environments.KeyOf(q.Frontier[j])] = j
 }
 
 // Push adds a new value
 func (q *PriorityNodeQueue) Push(x interface{}) {
 	node := x.(environments.Node)
-	q.NodeIndexes[node.Name()] = len(q.Frontier)
+	q.NodeIndexes[environments.KeyOf(node)] = len(q.Frontier)
 	q.Frontier = append(q.Frontier, node)
+	q.addOrder(node)
 }
 
 // Pop returns the value with the lowest
@@ -89,6 +220,32 @@ func (q *PriorityNodeQueue) Pop() interface{} {
 	n := len(old)
 	poppedValue := old[n-1]
 	q.Frontier = old[:n-1]
-	delete(q.NodeIndexes, poppedValue.Name())
+	delete(q.NodeIndexes, environments.KeyOf(poppedValue))
+	delete(q.order, environments.KeyOf(poppedValue))
 	return poppedValue
 }
+
+// Ordered returns the nodes of the frontier in the order
+// they would be popped in, without changing the queue
+func (q *PriorityNodeQueue) Ordered() []environments.Node {
+	clone := *q
+	clone.Frontier = append([]environments.Node(nil), q.Frontier...)
+
+	clone.NodeIndexes = make(map[environments.NodeKey]int, len(q.NodeIndexes))
+	for key, index := range q.NodeIndexes {
+		clone.NodeIndexes[key] = index
+	}
+
+	if q.order != nil {
+		clone.order = make(map[environments.NodeKey]int64, len(q.order))
+		for key, order := range q.order {
+			clone.order[key] = order
+		}
+	}
+
+	ordered := make([]environments.Node, 0, len(q.Frontier))
+	for clone.Len() > 0 {
+		ordered = append(ordered, heap.Pop(&clone).(environments.Node))
+	}
+	return ordered
+}
@@ -0,0 +1,210 @@
+package algorithms
+
+import (
+	"container/heap"
+	"fmt"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+// Solve runs the algorithm on the problem through its
+// environment, and returns the solution it found
+func Solve[S comparable, A any](algorithm Algorithm, ctx search.Context, p search.Problem[S, A]) (search.Solution[S, A], error) {
+	if err := p.Validate(); err != nil {
+		return search.Solution[S, A]{}, err
+	}
+
+	result, err := algorithm.Run(ctx, search.NewProblemEnvironment(p))
+	if err != nil {
+		return search.Solution[S, A]{}, err
+	}
+
+	if result.Node == nil {
+		return search.Solution[S, A]{}, fmt.Errorf("algorithm found a plan rather than a path")
+	}
+	return search.SolutionOf[S, A](result)
+}
+
+// SolveAStar runs A* on the problem directly, keeping costs
+// by state rather than by node name, so it doesn't need to
+// allocate a node or a name for every state it reaches
+func SolveAStar[S comparable, A any](ctx search.Context, p search.Problem[S, A]) (search.Solution[S, A], error) {
+	return solveBestFirst(ctx, p, problemOrder{
+		priority: func(cost, heuristic float64) float64 { return cost + heuristic },
+	})
+}
+
+// SolveUniformCost runs uniform cost search on
+// the problem directly, like SolveAStar
+func SolveUniformCost[S comparable, A any](ctx search.Context, p search.Problem[S, A]) (search.Solution[S, A], error) {
+	return solveBestFirst(ctx, p, problemOrder{
+		priority: func(cost, _ float64) float64 { return cost },
+	})
+}
+
+// SolveGreedyBestFirst runs greedy best first search on
+// the problem directly, like SolveAStar
+func SolveGreedyBestFirst[S comparable, A any](ctx search.Context, p search.Problem[S, A]) (search.Solution[S, A], error) {
+	return solveBestFirst(ctx, p, problemOrder{
+		priority: func(_, heuristic float64) float64 { return heuristic },
+		once:     true,
+	})
+}
+
+// SolveBreadthFirst runs breadth first search on
+// the problem directly, like SolveAStar
+func SolveBreadthFirst[S comparable, A any](ctx search.Context, p search.Problem[S, A]) (search.Solution[S, A], error) {
+	return solveBestFirst(ctx, p, problemOrder{
+		byDepth:  true,
+		priority: func(depth, _ float64) float64 { return depth },
+	})
+}
+
+// SolveDepthFirst runs depth first search on
+// the problem directly, like SolveAStar
+func SolveDepthFirst[S comparable, A any](ctx search.Context, p search.Problem[S, A]) (search.Solution[S, A], error) {
+	return solveBestFirst(ctx, p, problemOrder{
+		byDepth:  true,
+		priority: func(depth, _ float64) float64 { return -depth },
+	})
+}
+
+// problemOrder is the order a best first
+// search expands a problem's states in
+type problemOrder struct {
+	// byDepth compares the routes to a state by their
+	// depth rather than their cost, which then isn't checked
+	byDepth bool
+
+	// priority returns the priority of a state from the
+	// value of its route (its cost, or its depth if byDepth
+	// is set) and its heuristic, with lower expanded first
+	priority func(value, heuristic float64) float64
+
+	// once keeps states from being reached again
+	// after they're first reached
+	once bool
+}
+
+// solveBestFirst expands the problem's states in the order,
+// reaching a state again only if it has a lower value
+func solveBestFirst[S comparable, A any](ctx search.Context, p search.Problem[S, A], order problemOrder) (search.Solution[S, A], error) {
+	if err := p.Validate(); err != nil {
+		return search.Solution[S, A]{}, err
+	}
+
+	heuristic := p.Heuristic
+	if heuristic == nil {
+		heuristic = func(S) float64 { return 0 }
+	}
+	stateName := p.StateName
+	if stateName == nil {
+		stateName = func(state S) string { return fmt.Sprint(state) }
+	}
+
+	type reached struct {
+		parent S
+		action A
+
+		// cost is the cost of the route to the state,
+		// and value is what routes are compared by
+		cost, value float64
+	}
+	routes := map[S]reached{p.Start: {}}
+
+	queue := &stateQueue[S]{index: map[S]int{}}
+	heap.Push(queue, queuedState[S]{state: p.Start, priority: order.priority(0, heuristic(p.Start))})
+
+	iterations := 0
+	for queue.Len() > 0 {
+		if ctx.Cancelled() {
+			return search.Solution[S, A]{Iterations: iterations}, search.ErrCancelled
+		}
+		iterations++
+
+		current := heap.Pop(queue).(queuedState[S]).state
+		if p.IsGoal(current) {
+			solution := search.Solution[S, A]{
+				Cost:       routes[current].cost,
+				Iterations: iterations,
+			}
+			for state := current; ; {
+				solution.States = append([]S{state}, solution.States...)
+				if state == p.Start {
+					break
+				}
+				route := routes[state]
+				solution.Actions = append([]A{route.action}, solution.Actions...)
+				state = route.parent
+			}
+			return solution, nil
+		}
+
+		for _, successor := range p.Successors(current) {
+			if successor.Cost < 0 && !order.byDepth {
+				return search.Solution[S, A]{Iterations: iterations}, &environments.NegativeCostError{
+					Parent: stateName(current),
+					Child:  stateName(successor.State),
+					Cost:   successor.Cost,
+				}
+			}
+
+			route := reached{
+				parent: current,
+				action: successor.Action,
+				cost:   routes[current].cost + successor.Cost,
+				value:  routes[current].value + successor.Cost,
+			}
+			if order.byDepth {
+				route.value = routes[current].value + 1
+			}
+
+			if previous, seen := routes[successor.State]; seen && (order.once || previous.value <= route.value) {
+				continue
+			}
+			routes[successor.State] = route
+
+			priority := order.priority(route.value, heuristic(successor.State))
+			if i, inQueue := queue.index[successor.State]; inQueue {
+				queue.items[i].priority = priority
+				heap.Fix(queue, i)
+			} else {
+				heap.Push(queue, queuedState[S]{state: successor.State, priority: priority})
+			}
+		}
+	}
+	return search.Solution[S, A]{Iterations: iterations}, fmt.Errorf("frontier is empty; searched entire space, but could not find goal state")
+}
+
+type queuedState[S comparable] struct {
+	state    S
+	priority float64
+}
+
+// stateQueue implements heap.Interface, popping the state
+// with the lowest priority, and keeping the index of each
+type stateQueue[S comparable] struct {
+	items []queuedState[S]
+	index map[S]int
+}
+
+func (q *stateQueue[S]) Len() int           { return len(q.items) }
+func (q *stateQueue[S]) Less(i, j int) bool { return q.items[i].priority < q.items[j].priority }
+func (q *stateQueue[S]) Swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+	q.index[q.items[i].state] = i
+	q.index[q.items[j].state] = j
+}
+func (q *stateQueue[S]) Push(x interface{}) {
+	item := x.(queuedState[S])
+	q.index[item.state] = len(q.items)
+	q.items = append(q.items, item)
+}
+func (q *stateQueue[S]) Pop() interface{} {
+	n := len(q.items)
+	popped := q.items[n-1]
+	q.items = q.items[:n-1]
+	delete(q.index, popped.state)
+	return popped
+}
@@ -2,6 +2,7 @@ package algorithms
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/porgull/go-search/pkg/environments"
 	"github.com/porgull/go-search/pkg/search"
@@ -12,7 +13,18 @@ import (
 type RecursiveBestFirstSearch struct {
 	queue *PriorityNodeQueue
 
-	fLimits map[string]int
+	fLimits map[environments.NodeKey]float64
+
+	// onPath contains the nodes on the path being explored,
+	// which aren't explored again through their descendants,
+	// as a cycle of zero cost would never raise their f-limit
+	onPath map[environments.NodeKey]bool
+
+	// ctx is checked for cancellation before expanding nodes
+	ctx search.Context
+
+	// err is the error that stopped the search early
+	err error
 
 	iterations int
 }
@@ -21,7 +33,7 @@ type RecursiveBestFirstSearch struct {
 func (a RecursiveBestFirstSearch) Run(ctx search.Context, e environments.Environment) (search.Result, error) {
 	a.setStart(e.Start())
 
-	node, err := a.findGoal(e)
+	node, err := a.findGoal(ctx, e)
 	if err != nil {
 		return search.Result{}, err
 	}
@@ -35,138 +47,118 @@ func (a RecursiveBestFirstSearch) Run(ctx search.Context, e environments.Environ
 
 // initialize RecursiveBestFirstSearch's fields for this environment
 func (a *RecursiveBestFirstSearch) setStart(start environments.Node) {
-	a.fLimits = make(map[string]int, 512)
-	a.fLimits[start.Name()] = -1
+	a.fLimits = make(map[environments.NodeKey]float64, 512)
+	a.fLimits[environments.KeyOf(start)] = environments.HeuristicOf(start)
+
+	a.onPath = make(map[environments.NodeKey]bool, 64)
+	a.err = nil
 
 	a.iterations = 0
 
 }
 
 // find and return the goal node
-func (a *RecursiveBestFirstSearch) findGoal(e environments.Environment) (environments.Node, error) {
-	node, _ := a.recurse(e, e.Start(), -1)
+func (a *RecursiveBestFirstSearch) findGoal(ctx search.Context, e environments.Environment) (environments.Node, error) {
+	a.ctx = ctx
+	node, _ := a.recurse(e, e.Start(), math.Inf(1))
+	if ctx.Cancelled() {
+		return nil, search.ErrCancelled
+	}
+	if a.err != nil {
+		return nil, a.err
+	}
 	if node == nil {
 		return nil, fmt.Errorf("explored entire search space, but could not find goal node")
 	}
 	return node, nil
 }
 
-func (a *RecursiveBestFirstSearch) recurse(e environments.Environment, node environments.Node, fLimit int) (environments.Node, int) {
+func (a *RecursiveBestFirstSearch) recurse(e environments.Environment, node environments.Node, fLimit float64) (environments.Node, float64) {
 	if e.IsGoalNode(node) {
 		return node, 0
 	}
+	if a.ctx.Cancelled() {
+		return nil, math.Inf(1)
+	}
 	a.iterations++
 
-	children := node.Children()
+	nodeKey := environments.KeyOf(node)
+	a.onPath[nodeKey] = true
+	defer delete(a.onPath, nodeKey)
 
-	if len(children) == 0 {
-		fmt.Println("no children")
-		return nil, -1
-	}
+	children := make([]environments.Node, 0, 8)
+	for _, child := range node.Children() {
+		if err := environments.CheckCost(node, child); err != nil {
+			a.err = err
+			return nil, math.Inf(1)
+		}
 
-	for _, child := range children {
-		//_, hasPrevFLimit := a.fLimits[child.Name()]
-		currChildF := a.totalCost(child) + child.Heuristic()
+		childKey := environments.KeyOf(child)
+		if a.onPath[childKey] {
+			continue
+		}
+		children = append(children, child)
 
-		a.fLimits[child.Name()] = rbfsmax(currChildF, a.fLimits[node.Name()])
+		currChildF := a.totalCost(child) + environments.HeuristicOf(child)
+		a.fLimits[childKey] = math.Max(currChildF, a.fLimits[nodeKey])
+	}
 
+	if len(children) == 0 {
+		return nil, math.Inf(1)
 	}
 
 	for {
-		bestF, bestIdx := -1, -1
+		bestF, bestIdx := math.Inf(1), -1
 
 		for idx, child := range children {
 			if bestIdx == -1 {
-				bestF = a.fLimits[child.Name()]
+				bestF = a.fLimits[environments.KeyOf(child)]
 				bestIdx = idx
 			}
-			if rbfslessthan(a.fLimits[child.Name()], bestF) {
-				bestF = a.fLimits[child.Name()]
+			if a.fLimits[environments.KeyOf(child)] < bestF {
+				bestF = a.fLimits[environments.KeyOf(child)]
 				bestIdx = idx
 			}
 		}
 
-		if rbfsgreaterthan(bestF, fLimit) {
+		// every child is a dead end once its
+		// f-limit is infinite
+		if bestF > fLimit || math.IsInf(bestF, 1) {
 			return nil, bestF
 		}
 
-		altF, setAlt := -1, false
+		altF, setAlt := math.Inf(1), false
 		for idx, child := range children {
 			if idx == bestIdx {
 				continue
 			}
 			if !setAlt {
-				altF = a.fLimits[child.Name()]
+				altF = a.fLimits[environments.KeyOf(child)]
 				setAlt = true
 			}
 
-			if rbfslessthan(a.fLimits[child.Name()], altF) {
-				altF = a.fLimits[child.Name()]
+			if a.fLimits[environments.KeyOf(child)] < altF {
+				altF = a.fLimits[environments.KeyOf(child)]
 			}
 		}
 
-		result, bestF := a.recurse(e, children[bestIdx], rbfsmin(fLimit, altF))
-		a.fLimits[children[bestIdx].Name()] = bestF // now that search has been conducted,
+		result, bestF := a.recurse(e, children[bestIdx], math.Min(fLimit, altF))
+		a.fLimits[environments.KeyOf(children[bestIdx])] = bestF // now that search has been conducted,
 		if result != nil {
 			return result, 0
 		}
+		if a.ctx.Cancelled() || a.err != nil {
+			return nil, math.Inf(1)
+		}
 	}
 }
 
-func (a *RecursiveBestFirstSearch) totalCost(child environments.Node) int {
-	cost := child.Cost()
+func (a *RecursiveBestFirstSearch) totalCost(child environments.Node) float64 {
+	cost := environments.CostOf(child)
 	parent := child.Parent()
 	for parent != nil {
-		cost += parent.Cost()
+		cost += environments.CostOf(parent)
 		parent = parent.Parent()
 	}
 	return cost
 }
-
-func rbfsmax(a, b int) int {
-	// -1 == inf
-	if a == -1 {
-		return b
-	}
-	if b == -1 {
-		return a
-	}
-	if a > b {
-		return a
-	}
-	return b
-}
-
-func rbfsmin(a, b int) int {
-	// -1 == inf
-	if a == -1 {
-		return b
-	}
-	if b == -1 {
-		return a
-	}
-	if a > b {
-		return b
-	}
-	return a
-}
-
-func rbfslessthan(a, b int) bool {
-	if a == -1 && b != -1 {
-		return false
-	}
-	if b == -1 && a != -1 {
-		return true
-	}
-	return a < b
-}
-
-func rbfsgreaterthan(a, b int) bool {
-	if a == -1 && b != -1 {
-		return true
-	}
-	if b == -1 && a != -1 {
-		return false
-	}
-	return a > b
-}
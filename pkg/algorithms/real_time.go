@@ -0,0 +1,266 @@
+package algorithms
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+const (
+	defaultLookahead      = 1
+	defaultLRTAStarTrials = 10
+	defaultRealTimeSteps  = 100000
+)
+
+// LearningRealTimeAStar implements LRTA*, which interleaves
+// planning and acting: from its current node it looks
+// `lookahead` steps ahead, moves to the most promising
+// child, and raises the current node's heuristic to the
+// value it found. The learned heuristics are kept across
+// `trials` runs from the start node, which converge on the
+// optimal path. It can take the `lookahead`, `trials` and
+// `max_steps` (per trial) custom arguments
+type LearningRealTimeAStar struct {
+	realTimeSearch
+}
+
+// RealTimeAStar implements RTA*, which is LRTA* except that
+// the current node's heuristic is set to the value of the
+// second most promising child. This is better for a single
+// trial, but the learned heuristics can overestimate, so it
+// runs a single trial by default. It can take the
+// `lookahead`, `trials` and `max_steps` custom arguments
+type RealTimeAStar struct {
+	realTimeSearch
+}
+
+// Run runs LRTA* on the environment and returns the path
+// taken during the last trial
+func (a LearningRealTimeAStar) Run(ctx search.Context, e environments.Environment) (search.Result, error) {
+	if err := a.setParams(ctx.CustomSearchParams, defaultLRTAStarTrials); err != nil {
+		return search.Result{}, err
+	}
+
+	return a.getResult(ctx, e)
+}
+
+// Run runs RTA* on the environment and returns the path
+// taken during the last trial
+func (a RealTimeAStar) Run(ctx search.Context, e environments.Environment) (search.Result, error) {
+	if err := a.setParams(ctx.CustomSearchParams, 1); err != nil {
+		return search.Result{}, err
+	}
+	a.secondBest = true
+
+	return a.getResult(ctx, e)
+}
+
+// realTimeSearch contains the logic shared between
+// LRTA* and RTA*
+type realTimeSearch struct {
+	// secondBest is true if the learned heuristic is
+	// the second best child's value rather than the best
+	secondBest bool
+
+	lookahead int
+	trials    int
+	maxSteps  int
+
+	// heuristic is the learned heuristic, which
+	// overrides Node.Heuristic once it is set
+	heuristic map[environments.NodeKey]float64
+
+	iterations int
+}
+
+func (a *realTimeSearch) setParams(params search.CustomSearchParams, defaultTrials int) error {
+	var err error
+
+	if a.lookahead, err = intParam(params, "lookahead", defaultLookahead); err != nil {
+		return err
+	}
+	if a.lookahead < 1 {
+		return fmt.Errorf("'lookahead' must be at least 1")
+	}
+
+	if a.trials, err = intParam(params, "trials", defaultTrials); err != nil {
+		return err
+	}
+	if a.trials < 1 {
+		return fmt.Errorf("'trials' must be at least 1")
+	}
+
+	if a.maxSteps, err = intParam(params, "max_steps", defaultRealTimeSteps); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getResult runs every trial, keeping the
+// learned heuristics between them
+func (a *realTimeSearch) getResult(ctx search.Context, e environments.Environment) (search.Result, error) {
+	a.heuristic = make(map[environments.NodeKey]float64, 512)
+	a.iterations = 0
+
+	var node environments.Node
+	trialCosts := make([]string, a.trials)
+	for trial := 0; trial < a.trials; trial++ {
+		var err error
+		node, err = a.runTrial(ctx, e)
+		if errors.Is(err, search.ErrCancelled) {
+			return search.Result{}, err
+		}
+		if err != nil {
+			return search.Result{
+				Iterations:  a.iterations,
+				Environment: e,
+			}, fmt.Errorf("trial %d: %w", trial+1, err)
+		}
+
+		trialCosts[trial] = search.FormatCost(search.Result{Node: node}.TotalCost())
+	}
+
+	return search.Result{
+		Node:        node,
+		Iterations:  a.iterations,
+		Environment: e,
+		CustomResultStats: map[string]string{
+			"trial_costs": strings.Join(trialCosts, ", "),
+		},
+	}, nil
+}
+
+// runTrial acts from the start node until it reaches
+// the goal, returning the goal node
+func (a *realTimeSearch) runTrial(ctx search.Context, e environments.Environment) (environments.Node, error) {
+	current := e.Start()
+	for step := 0; step < a.maxSteps; step++ {
+		if e.IsGoalNode(current) {
+			return current, nil
+		}
+		if ctx.Cancelled() {
+			return nil, search.ErrCancelled
+		}
+
+		children := current.Children()
+		if len(children) == 0 {
+			return nil, fmt.Errorf("reached %s, which has no children", current.Name())
+		}
+
+		var best environments.Node
+		var bestF, secondF float64
+		hasSecond := false
+		for _, child := range children {
+			if err := environments.CheckCost(current, child); err != nil {
+				return nil, err
+			}
+			value, err := a.lookaheadValue(e, child, a.lookahead-1)
+			if err != nil {
+				return nil, err
+			}
+
+			f := environments.CostOf(child) + value
+			if best == nil || f < bestF {
+				if best != nil {
+					secondF, hasSecond = bestF, true
+				}
+				best, bestF = child, f
+			} else if !hasSecond || f < secondF {
+				secondF, hasSecond = f, true
+			}
+		}
+
+		// learn from the lookahead, so that coming back
+		// to this node is less appealing
+		if a.secondBest && hasSecond {
+			a.heuristic[environments.KeyOf(current)] = secondF
+		} else {
+			a.heuristic[environments.KeyOf(current)] = bestF
+		}
+
+		current = best
+	}
+	return nil, fmt.Errorf("did not reach goal node within %d steps", a.maxSteps)
+}
+
+// lookaheadValue returns the lowest cost plus learned
+// heuristic of the nodes up to depth steps below the node
+func (a *realTimeSearch) lookaheadValue(e environments.Environment, node environments.Node, depth int) (float64, error) {
+	a.iterations++
+
+	if e.IsGoalNode(node) {
+		return 0, nil
+	}
+
+	if depth == 0 {
+		return a.learnedHeuristic(node), nil
+	}
+
+	var best float64
+	found := false
+	for _, child := range node.Children() {
+		if err := environments.CheckCost(node, child); err != nil {
+			return 0, err
+		}
+		value, err := a.lookaheadValue(e, child, depth-1)
+		if err != nil {
+			return 0, err
+		}
+
+		if value += environments.CostOf(child); !found || value < best {
+			best, found = value, true
+		}
+	}
+
+	// the learned heuristic is also a lower bound, so
+	// use it if it's higher than what the lookahead
+	// found (or if the node is a dead end)
+	if h := a.learnedHeuristic(node); !found || h > best {
+		return h, nil
+	}
+	return best, nil
+}
+
+// learnedHeuristic returns the learned heuristic of the
+// node, falling back on the node's own heuristic
+func (a *realTimeSearch) learnedHeuristic(node environments.Node) float64 {
+	if h, ok := a.heuristic[environments.KeyOf(node)]; ok {
+		return h
+	}
+	return environments.HeuristicOf(node)
+}
+
+// intParam parses the integer custom argument,
+// using the default if it isn't supplied
+func intParam(params search.CustomSearchParams, name string, defaultValue int) (int, error) {
+	value, ok := params[name]
+	if !ok {
+		return defaultValue, nil
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("Could not parse '%s' as integer: %w", name, err)
+	}
+	return int(parsed), nil
+}
+
+// floatParam parses the float custom argument,
+// using the default if it isn't supplied
+func floatParam(params search.CustomSearchParams, name string, defaultValue float64) (float64, error) {
+	value, ok := params[name]
+	if !ok {
+		return defaultValue, nil
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Could not parse '%s' as float: %w", name, err)
+	}
+	return parsed, nil
+}
@@ -25,6 +25,20 @@ var (
 		"depth_limited":       DepthLimited{},
 		"iterative_deepening": IterativeDeepening{},
 		"rbfs":                RecursiveBestFirstSearch{},
+		"expectimax":          Expectimax{},
+		"expectiminimax":      Expectiminimax{},
+		"and_or":              AndOrSearch{},
+		"lrta*":               LearningRealTimeAStar{},
+		"rta*":                RealTimeAStar{},
+		"theta*":              ThetaStar{},
+		"lazy_theta*":         LazyThetaStar{},
+		"hpa*":                HierarchicalAStar{},
+		"cbs":                 ConflictBasedSearch{},
+		"parallel_a*":         ParallelAStar{},
+		"k_shortest":          KShortestPaths{},
+		"fringe":              FringeSearch{},
+		"focal":               FocalSearch{},
+		"bellman_ford":        BellmanFord{},
 	}
 )
 
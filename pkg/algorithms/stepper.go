@@ -0,0 +1,157 @@
+package algorithms
+
+import (
+	"fmt"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+// Stepper is implemented by the algorithms that can be run
+// one expansion at a time, so their frontier and closed set
+// can be inspected (e.g. to show a search in a UI) between
+// the steps. Their Run is the same as stepping until done
+type Stepper interface {
+	// Start starts a new search of the environment
+	Start(ctx search.Context, e environments.Environment) error
+
+	// Step expands the next node on the frontier, and returns
+	// what happened. done is true once the search has ended,
+	// after which Step doesn't do anything
+	Step() (event StepEvent, done bool)
+
+	// Frontier returns the nodes waiting to be
+	// expanded, in the order they'll be expanded
+	Frontier() []environments.Node
+
+	// Closed returns the nodes that have been
+	// expanded, in the order they were expanded,
+	// which aren't kept if the closed list is
+	// stored on disk (see ClosedList)
+	Closed() []environments.Node
+
+	// Result returns the result of the search
+	// so far, which is final once it's done
+	Result() (search.Result, error)
+}
+
+// StepEvent is what happened in a step of a search
+type StepEvent struct {
+	// Iteration is the number of nodes expanded,
+	// including the one expanded in this step
+	Iteration int
+
+	// Expanded is the node taken off the frontier
+	Expanded environments.Node
+
+	// Added contains the children of Expanded that
+	// were added to the frontier, or moved in it as
+	// a better path to them was found
+	Added []environments.Node
+
+	// Goal is true if Expanded is a goal node
+	Goal bool
+}
+
+var (
+	// steppers creates the algorithms
+	// that can be stepped through
+	steppers = map[string]func() Stepper{
+		"a*":            func() Stepper { return &AStar{} },
+		"breadth_first": func() Stepper { return &BreadthFirst{} },
+		"depth_first":   func() Stepper { return &DepthFirst{} },
+		"uniform_cost":  func() Stepper { return &UniformCost{} },
+	}
+)
+
+// GetStepper returns a new instance of the desired
+// algorithm, if it can be stepped through
+func GetStepper(name string) (Stepper, error) {
+	if newStepper, ok := steppers[name]; ok {
+		return newStepper(), nil
+	}
+	if _, ok := algorithms[name]; ok {
+		return nil, fmt.Errorf("algorithm %s can't be stepped through", name)
+	}
+	return nil, fmt.Errorf("could not find algorithm %s", name)
+}
+
+// runSteps steps through the started search until
+// it's done, which is how steppers implement Run
+func runSteps(s Stepper) (search.Result, error) {
+	for {
+		if _, done := s.Step(); done {
+			return s.Result()
+		}
+	}
+}
+
+// stepState contains what the steppers keep between
+// steps, other than their frontier and costs
+type stepState struct {
+	ctx search.Context
+	env environments.Environment
+
+	// forget is set if the expanded nodes
+	// aren't kept in closed
+	closed []environments.Node
+	forget bool
+	goal   environments.Node
+	err    error
+	done   bool
+
+	iterations int
+}
+
+// start resets the state for a new search
+func (s *stepState) start(ctx search.Context, e environments.Environment) {
+	*s = stepState{
+		ctx:    ctx,
+		env:    e,
+		closed: make([]environments.Node, 0, 512),
+	}
+}
+
+// expand records the node as expanded, and
+// returns the event of expanding it
+func (s *stepState) expand(node environments.Node) StepEvent {
+	s.iterations++
+	if !s.forget {
+		s.closed = append(s.closed, node)
+	}
+	return StepEvent{
+		Iteration: s.iterations,
+		Expanded:  node,
+	}
+}
+
+// forgetClosed stops keeping the expanded nodes,
+// e.g. as they're stored on disk instead
+func (s *stepState) forgetClosed() {
+	s.closed = nil
+	s.forget = true
+}
+
+// stop ends the search with the goal node,
+// or with the error if it wasn't found
+func (s *stepState) stop(goal environments.Node, err error) {
+	s.goal = goal
+	s.err = err
+	s.done = true
+}
+
+// Closed returns the nodes that have been
+// expanded, in the order they were expanded
+func (s *stepState) Closed() []environments.Node {
+	return append([]environments.Node(nil), s.closed...)
+}
+
+// Result returns the result of the search so far,
+// which only has a node once the goal is found
+func (s *stepState) Result() (search.Result, error) {
+	return search.Result{
+		Node:        s.goal,
+		Iterations:  s.iterations,
+		Environment: s.env,
+	}, s.err
+}
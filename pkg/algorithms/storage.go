@@ -0,0 +1,248 @@
+package algorithms
+
+import (
+	"container/heap"
+	"fmt"
+	"os"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+// OpenList stores the frontier of a search,
+// i.e. the nodes waiting to be expanded
+type OpenList interface {
+	// Push adds the node with the priority. If a node with the
+	// same key is already on the list, the one with the lower
+	// priority is popped first, and whether the other is still
+	// popped later is up to the list (see ClosedList.Cost)
+	Push(node environments.Node, priority float64) error
+
+	// Pop removes the node with the lowest priority from
+	// the list, and returns it with its priority
+	Pop() (environments.Node, float64, error)
+
+	// Len returns the number of nodes on the list
+	Len() int
+
+	// Nodes returns the nodes on the list,
+	// in the order they'll be popped
+	Nodes() ([]environments.Node, error)
+
+	// Close releases what the list is stored in
+	Close() error
+}
+
+// ClosedList stores the lowest cost every node of
+// a search was reached with, which is its depth in
+// breadth first search
+type ClosedList interface {
+	// Reach records that the node was reached with the cost,
+	// unless it was reached with the same or a lower cost
+	// before, and reports if it was recorded
+	Reach(node environments.Node, cost float64) (bool, error)
+
+	// Cost returns the cost the node with the key was
+	// reached with, and false if it wasn't reached
+	Cost(key environments.NodeKey) (float64, bool, error)
+
+	// Path returns the node with the parents it was
+	// reached through, which nodes popped from an
+	// OpenList stored on disk don't have
+	Path(node environments.Node) (environments.Node, error)
+
+	// Len returns the number of nodes reached
+	Len() int
+
+	// Close releases what the list is stored in
+	Close() error
+}
+
+// lists contains the open and closed lists of a search,
+// and the directory they're stored in if they're on disk
+type lists struct {
+	openList   OpenList
+	closedList ClosedList
+	dir        string
+}
+
+// newLists returns the open and closed lists holding the start
+// with its priority. They're stored in memory, unless the `storage`
+// custom argument is disk, in which case each keeps `storage_limit`
+// nodes in memory and spills the rest to a directory it creates in
+// `storage_dir`. The memory lists use the config for their queue
+func newLists(ctx search.Context, e environments.Environment, start environments.Node, priority float64, config PriorityNodeQueueConfig) (lists, error) {
+	storage, ok := ctx.CustomSearchParams["storage"]
+	if !ok || storage == "memory" {
+		closedList := &memoryClosedList{cost: make(map[environments.NodeKey]float64, 512)}
+		closedList.cost[environments.KeyOf(start)] = priority
+
+		config.CostMap = closedList.cost
+		openList := newMemoryOpenList(start, priority, config)
+		return lists{openList: openList, closedList: closedList}, nil
+	} else if storage != "disk" {
+		return lists{}, fmt.Errorf("unknown 'storage' %s, must be memory or disk", storage)
+	}
+
+	encodable, ok := e.(environments.EncodableEnvironment)
+	if !ok {
+		return lists{}, fmt.Errorf("environment %s can't be stored on disk", e.Name())
+	}
+	if config.TieBreak != TieBreakNone && config.TieBreak != TieBreakFIFO {
+		return lists{}, fmt.Errorf("nodes stored on disk can only break ties first in, first out")
+	}
+
+	limit, err := intParam(ctx.CustomSearchParams, "storage_limit", 1<<20)
+	if err != nil {
+		return lists{}, err
+	}
+	if limit < 1 {
+		return lists{}, fmt.Errorf("'storage_limit' must be at least 1, but was %d", limit)
+	}
+
+	dir, ok := ctx.CustomSearchParams["storage_dir"]
+	if !ok {
+		dir = os.TempDir()
+	}
+	dir, err = os.MkdirTemp(dir, "go-search-")
+	if err != nil {
+		return lists{}, fmt.Errorf("could not create storage directory: %w", err)
+	}
+
+	l := lists{
+		openList:   newDiskOpenList(encodable, dir, limit),
+		closedList: newDiskClosedList(encodable, dir, limit),
+		dir:        dir,
+	}
+	if _, err = l.closedList.Reach(start, priority); err == nil {
+		err = l.openList.Push(start, priority)
+	}
+	if err != nil {
+		l.close()
+		return lists{}, err
+	}
+	return l, nil
+}
+
+// pop pops the next node from the open list, skipping the
+// nodes that were reached with a lower cost since they were
+// pushed, and returns nil if there aren't any left
+func (l lists) pop() (environments.Node, float64, error) {
+	for l.openList.Len() > 0 {
+		node, priority, err := l.openList.Pop()
+		if err != nil {
+			return nil, 0, err
+		}
+
+		cost, _, err := l.closedList.Cost(environments.KeyOf(node))
+		if err != nil {
+			return nil, 0, err
+		}
+		if priority <= cost {
+			return node, priority, nil
+		}
+	}
+	return nil, 0, nil
+}
+
+// close closes both lists, and removes the
+// directory they were stored in, if any
+func (l lists) close() error {
+	err := l.openList.Close()
+	if closeErr := l.closedList.Close(); err == nil {
+		err = closeErr
+	}
+	if l.dir != "" {
+		if removeErr := os.RemoveAll(l.dir); err == nil {
+			err = removeErr
+		}
+	}
+	return err
+}
+
+// memoryOpenList is an OpenList stored in a priority
+// queue, which moves nodes pushed again rather than
+// adding them twice
+type memoryOpenList struct {
+	queue    *PriorityNodeQueue
+	priority map[environments.NodeKey]float64
+}
+
+func newMemoryOpenList(start environments.Node, priority float64, config PriorityNodeQueueConfig) *memoryOpenList {
+	l := &memoryOpenList{priority: make(map[environments.NodeKey]float64, 512)}
+	l.priority[environments.KeyOf(start)] = priority
+	l.queue = NewPriorityNodeQueue(start, l.priority, config)
+	return l
+}
+
+// Push adds the node, or moves it if it's on the list
+func (l *memoryOpenList) Push(node environments.Node, priority float64) error {
+	key := environments.KeyOf(node)
+	l.priority[key] = priority
+
+	if index, ok := l.queue.NodeIndexes[key]; ok {
+		l.queue.Frontier[index] = node
+		heap.Fix(l.queue, index)
+	} else {
+		heap.Push(l.queue, node)
+	}
+	return nil
+}
+
+// Pop removes and returns the node with the lowest priority
+func (l *memoryOpenList) Pop() (environments.Node, float64, error) {
+	if l.queue.Len() == 0 {
+		return nil, 0, fmt.Errorf("open list is empty")
+	}
+
+	node := heap.Pop(l.queue).(environments.Node)
+	key := environments.KeyOf(node)
+	priority := l.priority[key]
+	delete(l.priority, key)
+	return node, priority, nil
+}
+
+func (l *memoryOpenList) Len() int {
+	return l.queue.Len()
+}
+
+func (l *memoryOpenList) Nodes() ([]environments.Node, error) {
+	return l.queue.Ordered(), nil
+}
+
+func (l *memoryOpenList) Close() error {
+	return nil
+}
+
+// memoryClosedList is a ClosedList stored in a map
+type memoryClosedList struct {
+	cost map[environments.NodeKey]float64
+}
+
+func (l *memoryClosedList) Reach(node environments.Node, cost float64) (bool, error) {
+	key := environments.KeyOf(node)
+	if previousCost, ok := l.cost[key]; ok && previousCost <= cost {
+		return false, nil
+	}
+	l.cost[key] = cost
+	return true, nil
+}
+
+func (l *memoryClosedList) Cost(key environments.NodeKey) (float64, bool, error) {
+	cost, ok := l.cost[key]
+	return cost, ok, nil
+}
+
+// Path returns the node, as nodes kept
+// in memory have their parents already
+func (l *memoryClosedList) Path(node environments.Node) (environments.Node, error) {
+	return node, nil
+}
+
+func (l *memoryClosedList) Len() int {
+	return len(l.cost)
+}
+
+func (l *memoryClosedList) Close() error {
+	return nil
+}
@@ -0,0 +1,209 @@
+package algorithms
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+// ThetaStar implements the Theta* any-angle search algorithm
+// for environments that support straight lines (see
+// environments.AnyAngleEnvironment). It is A*, except that a
+// child is connected straight to its grandparent whenever
+// there's a line of sight between them, so paths aren't
+// constrained to the children of each node. See
+// https://en.wikipedia.org/wiki/Theta*
+type ThetaStar struct {
+	thetaStarSearch
+}
+
+// LazyThetaStar implements the Lazy Theta* any-angle search
+// algorithm, which is Theta* except that it assumes children
+// can be connected to their grandparents and only checks the
+// line of sight when they are expanded, which performs far
+// fewer line of sight checks
+type LazyThetaStar struct {
+	thetaStarSearch
+}
+
+// Run runs Theta* on the environment and returns the result
+func (a ThetaStar) Run(ctx search.Context, e environments.Environment) (search.Result, error) {
+	return a.getResult(ctx, e)
+}
+
+// Run runs Lazy Theta* on the environment and returns the result
+func (a LazyThetaStar) Run(ctx search.Context, e environments.Environment) (search.Result, error) {
+	a.lazy = true
+	return a.getResult(ctx, e)
+}
+
+// thetaStarSearch contains the logic shared between
+// Theta* and Lazy Theta*
+type thetaStarSearch struct {
+	// lazy is true if line of sight is only
+	// checked when a node is expanded
+	lazy bool
+
+	env   environments.AnyAngleEnvironment
+	queue *PriorityNodeQueue
+
+	// cost is the cost of the path to each node
+	cost map[environments.NodeKey]float64
+
+	// priority is the cost with the straight
+	// line distance to the goal
+	priority map[environments.NodeKey]float64
+
+	// closed contains the expanded nodes
+	closed map[environments.NodeKey]environments.Node
+
+	iterations int
+}
+
+func (a *thetaStarSearch) getResult(ctx search.Context, e environments.Environment) (search.Result, error) {
+	env, ok := e.(environments.AnyAngleEnvironment)
+	if !ok {
+		return search.Result{}, fmt.Errorf("environment %s does not support any-angle paths", e.Name())
+	}
+
+	// lines of sight don't account for time, so
+	// they would pass through moving obstacles
+	if grid, ok := e.(*environments.GridEnvironment); ok && grid.Timed() {
+		return search.Result{}, fmt.Errorf("grid %s has moving obstacles, which any-angle paths can't avoid", e.Name())
+	}
+	a.env = env
+
+	a.setStart(e.Start())
+
+	node, err := a.findGoal(ctx)
+	if err != nil {
+		return search.Result{}, err
+	}
+
+	return search.Result{
+		Node:        node,
+		Iterations:  a.iterations,
+		Environment: e,
+		CustomResultStats: map[string]string{
+			"path_length": strconv.FormatFloat(a.pathLength(node), 'f', 4, 64),
+		},
+	}, nil
+}
+
+// initialize the fields for this environment
+func (a *thetaStarSearch) setStart(start environments.Node) {
+	a.cost = make(map[environments.NodeKey]float64, 512)
+	a.cost[environments.KeyOf(start)] = 0
+
+	a.priority = make(map[environments.NodeKey]float64, 512)
+	a.priority[environments.KeyOf(start)] = a.env.DistanceToGoal(start)
+
+	a.closed = make(map[environments.NodeKey]environments.Node, 512)
+
+	a.iterations = 0
+
+	a.queue = NewPriorityNodeQueue(start, a.priority, PriorityNodeQueueConfig{})
+}
+
+// find and return the goal node
+func (a *thetaStarSearch) findGoal(ctx search.Context) (environments.Node, error) {
+	for a.queue.Len() > 0 {
+		if ctx.Cancelled() {
+			return nil, search.ErrCancelled
+		}
+		a.iterations++
+		currentNode := heap.Pop(a.queue).(environments.Node)
+
+		if a.lazy {
+			currentNode = a.setVertex(currentNode)
+		}
+
+		if a.env.IsGoalNode(currentNode) {
+			return currentNode, nil
+		}
+		a.closed[environments.KeyOf(currentNode)] = currentNode
+
+		for _, child := range currentNode.Children() {
+			if _, closed := a.closed[environments.KeyOf(child)]; closed {
+				continue
+			}
+
+			// connect the child straight to the grandparent
+			// if possible, which lazily is always assumed
+			grandparent := currentNode.Parent()
+			if grandparent != nil && (a.lazy || a.env.LineOfSight(grandparent, child)) {
+				child = a.env.Connect(grandparent, child)
+			}
+
+			a.update(child)
+		}
+	}
+	return nil, fmt.Errorf("frontier is empty; searched entire space, but could not find goal state")
+}
+
+// update adds the node to the frontier, or replaces
+// the node in the frontier if it is a shorter route
+func (a *thetaStarSearch) update(node environments.Node) {
+	cost := a.cost[environments.KeyOf(node.Parent())] + environments.CostOf(node)
+
+	if previous, seen := a.cost[environments.KeyOf(node)]; seen && previous <= cost {
+		return
+	}
+
+	a.cost[environments.KeyOf(node)] = cost
+	a.priority[environments.KeyOf(node)] = cost + a.env.DistanceToGoal(node)
+	if currIdx, inQueue := a.queue.NodeIndexes[environments.KeyOf(node)]; inQueue {
+		a.queue.Frontier[currIdx] = node
+		heap.Fix(a.queue, currIdx)
+	} else {
+		heap.Push(a.queue, node)
+	}
+}
+
+// setVertex checks the line of sight to the node's parent
+// that Lazy Theta* assumed, and if it is blocked, reconnects
+// the node to its closest expanded neighbor instead
+func (a *thetaStarSearch) setVertex(node environments.Node) environments.Node {
+	parent := node.Parent()
+	if parent == nil || a.env.LineOfSight(parent, node) {
+		return node
+	}
+
+	var best environments.Node
+	bestCost := math.Inf(1)
+	for _, child := range node.Children() {
+		neighbor, closed := a.closed[environments.KeyOf(child)]
+		if !closed {
+			continue
+		}
+
+		connected := a.env.Connect(neighbor, node)
+		cost := a.cost[environments.KeyOf(neighbor)] + environments.CostOf(connected)
+		if cost < bestCost {
+			best, bestCost = connected, cost
+		}
+	}
+
+	// the node's children are its neighbors, so at least
+	// the neighbor it was first reached from is expanded
+	if best == nil {
+		return node
+	}
+
+	a.cost[environments.KeyOf(node)] = bestCost
+	return best
+}
+
+// pathLength returns the Euclidean length of the
+// straight lines from the start to the node
+func (a *thetaStarSearch) pathLength(node environments.Node) float64 {
+	length := 0.0
+	for parent := node.Parent(); parent != nil; node, parent = parent, parent.Parent() {
+		length += a.env.Distance(parent, node)
+	}
+	return length
+}
@@ -1,7 +1,6 @@
 package algorithms
 
 import (
-	"container/heap"
 	"fmt"
 
 	"github.com/porgull/go-search/pkg/environments"
@@ -9,80 +8,169 @@ import (
 )
 
 // UniformCost implements the uniform cost
-// search algorithm
+// search algorithm. It can take the `tie_break`
+// and `tie_break_seed` custom arguments (see TieBreak),
+// can be stepped through (see Stepper) and resumed
+// from a checkpoint (see Checkpointer). Its open and
+// closed lists can be stored on disk with the
+// `storage` custom argument (see newLists)
 type UniformCost struct {
-	queue *PriorityNodeQueue
+	stepState
+	lists
+}
 
-	cost map[string]int
+// Run runs uniform cost search on the environment and returns the result
+func (a UniformCost) Run(ctx search.Context, e environments.Environment) (search.Result, error) {
+	if err := a.Start(ctx, e); err != nil {
+		return search.Result{}, err
+	}
 
-	iterations int
+	// only steppers are asked for the expanded nodes
+	a.forgetClosed()
+	return runSteps(&a)
 }
 
-// Run runs A* on the environment and returns the result
-func (a UniformCost) Run(ctx search.Context, e environments.Environment) (search.Result, error) {
-	a.setStart(e.Start())
+// Start starts a new search of the environment
+func (a *UniformCost) Start(ctx search.Context, e environments.Environment) error {
+	config, err := tieBreakConfig(ctx.CustomSearchParams, PriorityNodeQueueConfig{})
+	if err != nil {
+		return err
+	}
+	lists, err := newLists(ctx, e, e.Start(), 0, config)
+	if err != nil {
+		return err
+	}
+	a.stepState.start(ctx, e)
+	a.lists = lists
+	if a.dir != "" {
+		a.forgetClosed()
+	}
+	return nil
+}
 
-	node, err := a.findGoal(e)
+// Frontier returns the nodes waiting to be expanded, in
+// the order they'll be expanded, or nil if they're stored
+// on disk and can't be read
+func (a *UniformCost) Frontier() []environments.Node {
+	nodes, err := a.openList.Nodes()
 	if err != nil {
-		return search.Result{}, err
+		return nil
 	}
+	return nodes
+}
 
-	return search.Result{
-		Node:        node,
-		Iterations:  a.iterations,
-		Environment: e,
-	}, nil
+// Checkpoint returns the state of the search so far,
+// which needs its lists to be stored in memory
+func (a *UniformCost) Checkpoint() (*Checkpoint, error) {
+	openList, ok := a.openList.(*memoryOpenList)
+	if !ok {
+		return nil, fmt.Errorf("only searches stored in memory can be checkpointed")
+	}
+	return newCheckpoint("uniform_cost", &a.stepState, openList.queue, a.closedList.(*memoryClosedList).cost)
 }
 
-// initialize fields for this environment
-func (a *UniformCost) setStart(start environments.Node) {
-	a.cost = make(map[string]int, 512)
-	a.cost[start.Name()] = 0
+// Resume continues the search of the environment from the
+// checkpoint, with its lists stored in memory
+func (a *UniformCost) Resume(ctx search.Context, e environments.Environment, c *Checkpoint) error {
+	config, err := tieBreakConfig(ctx.CustomSearchParams, PriorityNodeQueueConfig{})
+	if err != nil {
+		return err
+	}
+	if storage, ok := ctx.CustomSearchParams["storage"]; ok && storage != "memory" {
+		return fmt.Errorf("only searches stored in memory can be resumed")
+	}
+
+	frontier, err := c.restore("uniform_cost", e)
+	if err != nil {
+		return err
+	}
 
-	a.iterations = 0
+	a.stepState.start(ctx, e)
+	a.iterations = c.Iterations
 
-	a.queue = NewPriorityNodeQueue(start, a.cost, PriorityNodeQueueConfig{})
+	closedList := &memoryClosedList{cost: make(map[environments.NodeKey]float64, len(c.Costs))}
+	for key, cost := range c.Costs {
+		closedList.cost[key] = cost
+	}
+
+	openList := &memoryOpenList{priority: make(map[environments.NodeKey]float64, len(frontier))}
+	for _, node := range frontier {
+		key := environments.KeyOf(node)
+		openList.priority[key] = closedList.cost[key]
+	}
+	config.CostMap = closedList.cost
+	openList.queue = restoreQueue(frontier, openList.priority, config)
+
+	a.lists = lists{openList: openList, closedList: closedList}
+	return nil
 }
 
-// find and return the goal node
-func (a *UniformCost) findGoal(e environments.Environment) (environments.Node, error) {
-	// if nothing in queue/frontier, then it is impossible
-	// to find the goal node
-	for a.queue.Len() > 0 {
-		a.iterations++
-		currentNode := heap.Pop(a.queue).(environments.Node)
+// finish ends the search, and closes the lists
+func (a *UniformCost) finish(goal environments.Node, err error) {
+	if closeErr := a.lists.close(); err == nil && closeErr != nil {
+		goal, err = nil, closeErr
+	}
+	a.stop(goal, err)
+}
+
+// Step expands the node with the lowest cost, and returns what happened
+func (a *UniformCost) Step() (StepEvent, bool) {
+	if a.done {
+		return StepEvent{}, true
+	}
+	if a.ctx.Cancelled() {
+		a.finish(nil, search.ErrCancelled)
+		return StepEvent{}, true
+	}
 
-		if e.IsGoalNode(currentNode) {
-			return currentNode, nil
+	currentNode, currentCost, err := a.pop()
+	if err != nil {
+		a.finish(nil, err)
+		return StepEvent{}, true
+	} else if currentNode == nil {
+		a.finish(nil, fmt.Errorf("frontier is empty; searched entire space, but could not find goal state"))
+		return StepEvent{}, true
+	}
+	event := a.expand(currentNode)
+
+	if a.env.IsGoalNode(currentNode) {
+		event.Goal = true
+		a.finish(a.closedList.Path(currentNode))
+		return event, true
+	}
+
+	// the added children are kept in the slice of
+	// children, which isn't needed once they're added
+	children := currentNode.Children()
+	event.Added = children[:0]
+	for _, child := range children {
+		if err := environments.CheckCost(currentNode, child); err != nil {
+			a.finish(nil, err)
+			return event, true
 		}
 
-		for _, child := range currentNode.Children() {
-			childIdx, inQueue := a.queue.NodeIndexes[child.Name()]
-			prevCost, seenPrev := a.cost[child.Name()]
-			currCost := a.cost[currentNode.Name()] + child.Cost()
-
-			// if the cost of the node we just expanded
-			// is higher than the pre-existing node in
-			// the queue, skip this iteration
-			if seenPrev && prevCost < currCost {
-				continue
-			}
-
-			if !inQueue {
-				a.cost[child.Name()] = a.cost[currentNode.Name()] + child.Cost()
-				// new node, just add it
-				heap.Push(a.queue, child)
-			} else {
-
-				a.cost[child.Name()] = a.cost[currentNode.Name()] + child.Cost()
-				// we found a new route to the node. let's
-				// update the cost and fix its placement in the
-				// queue
-				a.queue.Frontier[childIdx] = child
-				heap.Fix(a.queue, childIdx)
-			}
+		// if the child was reached with a lower cost
+		// before, skip it, and otherwise add it or
+		// move it up the list with its new cost
+		childCost := currentCost + environments.CostOf(child)
+		reached, err := a.closedList.Reach(child, childCost)
+		if err == nil && reached {
+			err = a.openList.Push(child, childCost)
 		}
+		if err != nil {
+			a.finish(nil, err)
+			return event, true
+		}
+		if reached {
+			event.Added = append(event.Added, child)
+		}
+	}
 
+	// if nothing in queue/frontier, then it is impossible
+	// to find the goal node
+	if a.openList.Len() == 0 {
+		a.finish(nil, fmt.Errorf("frontier is empty; searched entire space, but could not find goal state"))
+		return event, true
 	}
-	return nil, fmt.Errorf("frontier is empty; searched entire space, but could not find goal state")
+	return event, false
 }
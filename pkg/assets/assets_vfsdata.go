@@ -41,6 +41,27 @@ var Assets = func() http.FileSystem {
 
 			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xaa\xe6\x52\x50\x50\x50\x50\x2a\xa9\x2c\x48\x55\xb2\x52\x50\x4a\x2f\xca\x4c\x51\xd2\x81\x88\x81\xd8\xf1\x79\x89\xb9\x60\x89\xe4\xfc\xa2\xbc\xd4\xa2\x62\x64\x39\x25\x2b\x85\x68\x30\x0f\x2c\xa2\xa5\x87\x0f\x40\xf5\x81\x55\xe2\x55\x38\xaa\x92\xba\x2a\x15\x95\xc0\x0a\x63\xb9\x6a\x01\x01\x00\x00\xff\xff\x30\x26\x82\xb8\xea\x01\x00\x00"),
 		},
+		"/environments/crossing.json": &vfsgen۰CompressedFileInfo{
+			name:             "crossing.json",
+			modTime:          time.Date(2026, 10, 19, 8, 53, 28, 203098147, time.UTC),
+			uncompressedSize: 276,
+
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x64\x8d\x4d\x0a\x83\x30\x14\x84\xf7\x9e\xe2\x75\x56\x6d\x11\xf1\xa7\xab\x5c\xa5\x14\xb1\x1a\x24\xd0\x1a\x49\x5c\xbc\x22\xde\xbd\xbc\x34\x52\x6d\xc3\x2c\x92\x6f\x3e\x32\x73\x42\x44\x84\xe9\x35\x6a\x28\x42\xef\x4c\x87\xf4\xc3\xe4\x5e\x0f\xcd\x33\x14\xad\xb3\xde\x9b\xa1\xdf\x96\x50\x74\x0d\x2f\x09\x98\x39\x63\xe6\x28\x48\x70\xce\xe4\x1c\x90\xfe\x5b\x81\xdc\xe2\x67\xf6\xee\xa7\xa6\x7d\x68\x0f\x45\xf3\xd7\xcd\x65\x00\xc7\x2a\xcd\x4f\x88\xaa\x04\xc5\xca\x8b\x3d\x2f\x57\x5e\xee\x79\xb5\xf5\x03\x5e\x7e\x86\xeb\x51\x3b\x63\x3b\x28\xba\x24\x4b\xf2\x1e\x00\x85\x82\x14\x67\x14\x01\x00\x00"),
+		},
+		"/environments/frozen_lake.json": &vfsgen۰CompressedFileInfo{
+			name:             "frozen_lake.json",
+			modTime:          time.Date(2026, 10, 19, 8, 35, 14, 400786665, time.UTC),
+			uncompressedSize: 226,
+
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x5c\x8c\xc1\x0a\xc2\x30\x10\x44\xef\xf9\x8a\x75\xbd\x49\x18\x44\xf0\x60\x7f\x45\xa4\xb4\xb6\x4a\x30\x26\x21\x8d\xb0\x55\xfc\x77\x49\x8c\x07\x4b\xe6\x90\x7d\x6f\x98\x97\x22\x22\xe2\x34\x87\x91\x1b\xe2\x6b\x34\x03\xeb\x2f\xcb\xff\xd6\x75\xf7\x22\x2e\xd1\x3f\x47\xd7\xda\xee\x36\xfe\xfc\x64\x4d\x68\x43\xf4\x7d\xd7\x1b\x6b\xd2\xcc\x0d\x6d\xb1\xab\x72\x30\xd3\xd9\x3f\x5c\x2a\xf0\xb0\xaf\x34\x4f\x72\x43\xc7\x72\xe5\xf0\x06\x80\x00\xa8\xa3\x39\x0c\x11\xe4\xf7\xc7\x00\x68\xad\x17\xbd\xb5\x40\x16\x3d\x81\x06\xb0\x62\x45\x44\x74\x52\x6f\xf5\x19\x00\xdb\xce\xc9\xb9\xe2\x00\x00\x00"),
+		},
+		"/environments/gamble.json": &vfsgen۰CompressedFileInfo{
+			name:             "gamble.json",
+			modTime:          time.Date(2026, 10, 19, 8, 32, 49, 572778056, time.UTC),
+			uncompressedSize: 1162,
+
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x92\xdf\x6a\xb4\x30\x10\xc5\xef\x7d\x8a\x61\xae\xe5\x43\xf9\x76\x7b\x91\x97\x91\xd9\x98\x5d\x6d\xf3\xa7\xc4\x71\x61\x29\xbe\x7b\x89\xd6\xa0\x52\x24\x5a\x27\x37\xe6\x9c\x9c\xf9\x65\xf4\x2b\x03\x00\x40\x7e\x7d\x2a\x14\x80\x1d\x13\x2b\xcc\xa7\xcd\x8e\xc9\x73\x65\x5d\x3d\x4a\x8d\x33\x51\x79\x38\xd2\x51\x70\xf7\x7b\x2b\xa3\xa4\xec\xb3\xf5\xce\x1a\x65\xb9\xb2\x64\x46\xc7\x83\xcc\x4d\x47\xc7\xd8\xa3\x43\x01\x53\xef\x50\x53\xf8\x72\x27\x14\x36\xaa\xf7\x6d\xc7\xad\x44\x01\x97\x22\x5f\x8b\xb2\x69\x75\xed\x95\x5d\x05\xcd\x85\xec\xb4\xae\xbc\xa3\x1a\x05\xfc\xdf\x1c\x0d\x0b\x6f\x24\x3f\x66\x43\x59\xac\xf4\x21\xbe\x0d\x79\xf6\x6b\xe2\x0e\x67\x79\x8c\xf3\x67\x78\x89\x0c\x4b\xe8\xb3\x0c\x8b\xbc\xb0\xd0\xf5\x2c\x9d\xd9\x7c\x90\xf9\x41\xa9\x15\x79\x14\x50\xfc\x7b\x5b\x9f\x0b\x85\xec\x29\xf0\x57\xef\x64\x46\xcf\x25\xe1\x0e\x73\xe2\x59\xfe\xbf\xce\x70\xcd\x7c\xe4\x8f\xa3\xfa\xa9\x7c\x47\xfe\x85\x02\xd8\xf7\xea\x1c\xe4\x36\x36\x14\xd6\x8a\x5d\x1f\xa6\x52\x5e\x13\xae\x10\xdd\x47\xe8\x13\xf1\xae\x29\x33\x8c\xee\x1d\x80\xbd\xfe\x8b\xd4\x0c\x00\x60\xc8\x86\xec\x7b\x00\xe1\x62\xca\x75\x8a\x04\x00\x00"),
+		},
 		"/environments/maze.json": &vfsgen۰CompressedFileInfo{
 			name:             "maze.json",
 			modTime:          time.Date(2020, 6, 28, 13, 9, 3, 764737065, time.UTC),
@@ -48,6 +69,27 @@ var Assets = func() http.FileSystem {
 
 			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x90\x51\x0a\xc2\x40\x0c\x44\xff\x7b\x8a\x98\x4f\x91\x39\x40\xaf\x22\x22\x05\x8b\xf8\x51\x11\xf1\x63\x54\xbc\xbb\x34\xd9\x2c\x71\xd7\xd0\x96\xed\xe4\xed\xec\x64\xdf\x83\x88\x88\x3e\x9e\xb7\x59\x47\xd1\xf3\xfd\x72\xd2\x9d\x6b\xeb\xfa\x78\x9d\x16\x6b\x2c\xd3\x6b\xce\x0d\x1d\x65\x6f\x7f\xa6\x6c\x01\x10\xa5\x98\x6b\x15\xca\x3e\x23\xe1\x12\xe9\x4f\x65\x60\xc2\x2f\x89\x0c\xfb\xeb\xc7\xb4\xa4\xb7\xe1\x9f\xc8\xe0\x41\xfe\x9e\x8e\x8a\xb3\xda\x13\x6c\x3c\x91\xd2\x21\x56\xbd\x27\x23\x28\x03\xaa\xfb\xba\x9c\xa8\x53\x14\xa4\x98\xb6\x64\x62\xe2\x8e\x58\xe2\x76\x64\x5b\x31\x20\xc0\x8d\x1a\x78\x18\x3e\xdf\x00\x00\x00\xff\xff\x56\xf1\xf8\x05\xe7\x01\x00\x00"),
 		},
+		"/environments/treasure_hunt.json": &vfsgen۰CompressedFileInfo{
+			name:             "treasure_hunt.json",
+			modTime:          time.Date(2026, 10, 19, 8, 35, 14, 405627031, time.UTC),
+			uncompressedSize: 2068,
+
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbc\x95\xd1\x6e\xea\x30\x0c\x86\xef\xf3\x14\x96\xaf\x7b\xaa\x96\x03\xd2\xd6\x97\xa9\xb2\xd6\xa2\xd9\xd2\xb4\x4a\x52\xd0\x34\xf5\xdd\xa7\xd2\x06\x35\x5b\x16\x40\x20\x92\xdc\x60\x5b\x8e\xff\x8f\x3f\xf0\xc5\x00\x00\xd0\x7e\xf6\x84\x05\x60\x5b\xf7\x98\xcc\x21\x63\xb9\xb6\xa5\xea\xea\x53\x82\x94\xd5\x5c\x55\xe4\xb2\xfb\x8e\xcb\x73\xf2\xc0\x07\x69\x5d\x86\xd4\x41\xe8\x4e\xb5\xa4\x6c\xa9\x78\x7b\x2a\xb0\x9a\xb8\x19\x34\x95\xcd\xa0\xce\x85\xb5\x30\x55\x37\x7d\x2e\x20\x4b\x5f\x97\xa0\xb1\xdc\x92\xc1\x02\xe6\xc1\x96\x8e\xcb\xdd\xeb\xe8\xb4\xb1\xa1\x41\x0b\x63\x45\x85\x05\xfc\x4f\xfc\x5c\xd5\x08\x59\x6b\x52\x5e\x2f\xb7\xb1\xe1\x52\x62\x01\xb9\x97\x19\x7f\xb4\xe0\x95\x15\x9d\xf2\xa7\x71\x0b\x8f\x5c\x7e\x04\x33\xd3\x41\x4d\x47\xae\x6b\x2c\xe0\x5f\x9e\xb0\x40\x01\xe0\xa4\xc9\x88\xbf\x2f\x88\x8f\xea\xd6\xc8\xe2\x91\x91\x05\xc4\xb9\x96\x11\x9a\x1b\x7f\xea\x0b\x34\x25\xd5\x7b\xba\x0f\xa7\x51\xc4\x9f\xc3\xd3\x0d\x9b\xa5\xbb\xe4\x22\xf5\x2c\xdd\x05\x6b\x7c\xca\x01\xb5\xd3\x41\x3d\xa8\x27\x4b\x5a\xde\x51\x68\x63\x2f\xe6\xb7\xf6\x48\x27\xb9\x9b\x23\x56\xca\x6f\xb2\xd2\xfc\x5b\x72\x97\x95\xde\x87\xb6\x7f\x0a\x76\x37\x6b\x96\xbe\x5c\x81\x7d\x13\x2c\xf1\x21\x07\xb4\x4e\x07\x2b\x29\xda\xb7\x6b\x34\x6d\x1f\xa5\xe9\x91\x26\x99\x8d\x17\xb1\xc8\xf6\x26\x8b\xac\xfe\x0f\xf2\x8c\x45\xd0\xc5\x6d\x72\x62\x5a\x76\xc3\xef\xd9\x02\x5c\xf3\x2c\x61\x81\x8a\x1b\xc0\xae\xa7\x0e\x56\x8d\x2c\x1e\x19\x59\x40\xe6\xf9\xfb\x8a\xd0\xcd\x22\x74\x57\x4d\x19\x00\xc0\xc8\x46\xf6\x3d\x00\xb6\x90\x02\x85\x14\x08\x00\x00"),
+		},
+		"/environments/vacuum_erratic.json": &vfsgen۰CompressedFileInfo{
+			name:             "vacuum_erratic.json",
+			modTime:          time.Date(2026, 10, 19, 8, 36, 49, 999657375, time.UTC),
+			uncompressedSize: 134,
+
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4c\xca\xb1\x0a\x02\x31\x0c\x87\xf1\xbd\x4f\xf1\x27\xb3\x83\x73\x5e\x45\xe4\x28\x67\x86\x0c\x4d\x8f\x98\x14\x8a\xf8\xee\x82\x16\xb9\xf1\xfb\xf8\xbd\x0a\x00\x50\xcc\x43\x88\x41\xa3\xee\x99\x8d\x2e\xbf\x2b\x36\xd4\xbb\x35\xb1\xd8\xac\xb6\x93\xd8\xc4\xbd\x86\xee\x7f\xb9\x92\x11\x9e\xb2\xe6\xd1\x9f\x1a\xda\x8d\x18\xd7\xb5\x1e\xea\x31\x89\x71\xfb\x32\x84\xa7\xdc\xcb\xbb\x7c\x06\x00\x25\x15\x46\x13\x86\x00\x00\x00"),
+		},
+		"/environments/warehouse.json": &vfsgen۰CompressedFileInfo{
+			name:             "warehouse.json",
+			modTime:          time.Date(2026, 10, 19, 8, 51, 55, 945386428, time.UTC),
+			uncompressedSize: 473,
+
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x8e\xd1\x0a\x82\x30\x18\x85\xef\xf7\x14\x87\xff\x2a\x61\x88\x99\x37\xfa\x2a\x21\xb2\x68\x98\xa0\x2e\xe6\x24\x43\x7c\xf7\xd8\xd2\x68\x83\x8a\xc6\x7f\xb1\x9d\xff\x7c\x3b\x67\x66\x00\x40\xe6\x7e\x95\x54\x80\xba\xb1\x35\x4d\x25\x6a\xd9\x9b\xaa\xd6\xcd\x99\xf8\x73\x6f\xef\x55\x2f\x3a\x67\xba\x09\x2d\x2f\x6a\x1c\xe4\xfb\x96\x0a\x1c\xdd\xcb\x0e\xc5\xaf\xb3\x7a\xec\x50\x3c\x4d\xeb\x78\xea\x66\xfd\xcf\xeb\xc4\x72\x6d\xe0\x1a\x0f\x5e\x87\x99\xb6\xbe\x5a\x9d\x94\xc1\x9e\x38\x68\x30\x42\x1b\xab\xed\x12\x9e\x44\x56\xa9\x95\x68\x9d\x90\xf3\x2c\xa2\x85\x7f\xe4\x53\x9f\xcf\x43\x3e\xf9\xc1\x1f\xc2\xfc\x34\xcc\x4f\xbf\xf2\x59\x98\x1f\xf0\xee\xc3\x85\x01\x40\xc9\x16\xf6\x18\x00\x38\x5f\xf7\x0c\xd9\x01\x00\x00"),
+		},
 	}
 	fs["/"].(*vfsgen۰DirInfo).entries = []os.FileInfo{
 		fs["/environments"].(os.FileInfo),
@@ -55,7 +97,13 @@ var Assets = func() http.FileSystem {
 	fs["/environments"].(*vfsgen۰DirInfo).entries = []os.FileInfo{
 		fs["/environments/bucharest.json"].(os.FileInfo),
 		fs["/environments/corners.json"].(os.FileInfo),
+		fs["/environments/crossing.json"].(os.FileInfo),
+		fs["/environments/frozen_lake.json"].(os.FileInfo),
+		fs["/environments/gamble.json"].(os.FileInfo),
 		fs["/environments/maze.json"].(os.FileInfo),
+		fs["/environments/treasure_hunt.json"].(os.FileInfo),
+		fs["/environments/vacuum_erratic.json"].(os.FileInfo),
+		fs["/environments/warehouse.json"].(os.FileInfo),
 	}
 
 	return fs
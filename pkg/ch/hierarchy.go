@@ -0,0 +1,348 @@
+// Package ch implements contraction hierarchies for state
+// environments, which precompute shortcuts between states so
+// that repeated shortest path queries on the same graph only
+// need to search a tiny part of it.
+//
+// States are contracted one at a time, from least to most
+// important. Contracting a state adds a shortcut between each
+// pair of its neighbors whose shortest path went through it.
+// Queries then only search upwards, to more important states,
+// from both the start and the goal.
+//
+// See https://en.wikipedia.org/wiki/Contraction_hierarchies
+package ch
+
+import (
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/porgull/go-search/pkg/environments"
+)
+
+const (
+	// witnessSearchLimit is the most states a witness search
+	// settles before giving up and adding the shortcut anyway,
+	// which only costs an unnecessary shortcut
+	witnessSearchLimit = 500
+)
+
+// Edge is a directed edge of the hierarchy, either
+// from the original graph or a shortcut
+type Edge struct {
+	From int     `json:"from"`
+	To   int     `json:"to"`
+	Cost float64 `json:"cost"`
+
+	// Via is the state a shortcut skips over,
+	// or -1 if the edge is from the original graph
+	Via int `json:"via"`
+}
+
+// Hierarchy is the contraction hierarchy of a graph
+type Hierarchy struct {
+	// Names contains the name of every state
+	Names []string `json:"names"`
+
+	// Ranks contains the order each
+	// state was contracted in
+	Ranks []int `json:"ranks"`
+
+	// Edges contains the original edges and the
+	// shortcuts added while contracting
+	Edges []Edge `json:"edges"`
+
+	indexes map[string]int
+
+	// upward contains the edges leaving each
+	// state to states of a higher rank
+	upward [][]Edge
+
+	// downward contains the edges arriving at each
+	// state from states of a higher rank
+	downward [][]Edge
+
+	// cheapest contains the cheapest edge
+	// between each pair of states
+	cheapest map[[2]int]Edge
+}
+
+// Build contracts every state of the graph
+func Build(states environments.States) *Hierarchy {
+	h := &Hierarchy{
+		Names: make([]string, 0, len(states)),
+	}
+	for name := range states {
+		h.Names = append(h.Names, name)
+	}
+	sort.Strings(h.Names)
+	h.index()
+
+	c := newContractor(len(h.Names))
+	for from, name := range h.Names {
+		for child, cost := range states[name].Children {
+			c.addEdge(Edge{From: from, To: h.indexes[child], Cost: cost, Via: -1})
+		}
+	}
+
+	h.Ranks, h.Edges = c.contractAll()
+	h.link()
+
+	return h
+}
+
+// Load reads a hierarchy saved with Save
+func Load(r io.Reader) (*Hierarchy, error) {
+	h := &Hierarchy{}
+	if err := json.NewDecoder(r).Decode(h); err != nil {
+		return nil, fmt.Errorf("could not decode hierarchy: %w", err)
+	}
+
+	if len(h.Ranks) != len(h.Names) {
+		return nil, fmt.Errorf("hierarchy has %d states but ranks for %d", len(h.Names), len(h.Ranks))
+	}
+
+	for _, edge := range h.Edges {
+		for _, state := range []int{edge.From, edge.To} {
+			if state < 0 || state >= len(h.Names) {
+				return nil, fmt.Errorf("edge refers to missing state %d", state)
+			}
+		}
+		if edge.Via < -1 || edge.Via >= len(h.Names) {
+			return nil, fmt.Errorf("shortcut refers to missing state %d", edge.Via)
+		}
+	}
+
+	h.index()
+	h.link()
+
+	return h, nil
+}
+
+// Save writes the hierarchy so it can be loaded later
+func (h *Hierarchy) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(h)
+}
+
+// Shortcuts returns the number of shortcuts added
+func (h *Hierarchy) Shortcuts() int {
+	total := 0
+	for _, edge := range h.Edges {
+		if edge.Via != -1 {
+			total++
+		}
+	}
+	return total
+}
+
+func (h *Hierarchy) index() {
+	h.indexes = make(map[string]int, len(h.Names))
+	for idx, name := range h.Names {
+		h.indexes[name] = idx
+	}
+}
+
+// link sorts the edges into the upward
+// and downward edges of each state
+func (h *Hierarchy) link() {
+	h.upward = make([][]Edge, len(h.Names))
+	h.downward = make([][]Edge, len(h.Names))
+	h.cheapest = make(map[[2]int]Edge, len(h.Edges))
+
+	for _, edge := range h.Edges {
+		if h.Ranks[edge.To] > h.Ranks[edge.From] {
+			h.upward[edge.From] = append(h.upward[edge.From], edge)
+		} else {
+			h.downward[edge.To] = append(h.downward[edge.To], edge)
+		}
+
+		pair := [2]int{edge.From, edge.To}
+		if previous, ok := h.cheapest[pair]; !ok || edge.Cost < previous.Cost {
+			h.cheapest[pair] = edge
+		}
+	}
+}
+
+// contractor contains the state used while contracting
+type contractor struct {
+	// out and in contain the cheapest edge from and to
+	// each state, to and from states not yet contracted
+	out []map[int]Edge
+	in  []map[int]Edge
+
+	// contractedNeighbors is used to spread out contraction
+	// across the graph, rather than contracting one area
+	contractedNeighbors []int
+
+	edges []Edge
+}
+
+func newContractor(size int) *contractor {
+	c := &contractor{
+		out:                 make([]map[int]Edge, size),
+		in:                  make([]map[int]Edge, size),
+		contractedNeighbors: make([]int, size),
+	}
+	for i := range c.out {
+		c.out[i] = make(map[int]Edge)
+		c.in[i] = make(map[int]Edge)
+	}
+	return c
+}
+
+// addEdge adds the edge unless there's already
+// a cheaper one between the same states
+func (c *contractor) addEdge(edge Edge) {
+	if edge.From == edge.To {
+		return
+	}
+	if previous, ok := c.out[edge.From][edge.To]; ok && previous.Cost <= edge.Cost {
+		return
+	}
+	c.out[edge.From][edge.To] = edge
+	c.in[edge.To][edge.From] = edge
+}
+
+// contractAll contracts every state, returning the rank
+// of each state and every edge of the hierarchy
+func (c *contractor) contractAll() ([]int, []Edge) {
+	for _, out := range c.out {
+		for _, edge := range out {
+			c.edges = append(c.edges, edge)
+		}
+	}
+
+	queue := &priorityQueue{}
+	for state := range c.out {
+		heap.Push(queue, queuedState{state: state, priority: c.priority(state)})
+	}
+
+	ranks := make([]int, len(c.out))
+	for rank := 0; queue.Len() > 0; {
+		next := heap.Pop(queue).(queuedState)
+
+		// contracting other states changes the priority, so
+		// update it and put it back if it's no longer the lowest
+		next.priority = c.priority(next.state)
+		if queue.Len() > 0 && next.priority > (*queue)[0].priority {
+			heap.Push(queue, next)
+			continue
+		}
+
+		for _, shortcut := range c.shortcuts(next.state) {
+			c.addEdge(shortcut)
+			c.edges = append(c.edges, shortcut)
+		}
+		c.remove(next.state)
+
+		ranks[next.state] = rank
+		rank++
+	}
+
+	return ranks, c.edges
+}
+
+// priority is lower for the states that should be contracted
+// first: those that add few shortcuts compared to the edges
+// they remove, and that have few contracted neighbors
+func (c *contractor) priority(state int) float64 {
+	edgeDifference := len(c.shortcuts(state)) - len(c.out[state]) - len(c.in[state])
+	return float64(edgeDifference + c.contractedNeighbors[state])
+}
+
+// shortcuts returns the shortcuts needed to
+// keep shortest paths when contracting the state
+func (c *contractor) shortcuts(state int) []Edge {
+	var out []Edge
+	for from, incoming := range c.in[state] {
+		maxCost := 0.0
+		for _, outgoing := range c.out[state] {
+			if incoming.Cost+outgoing.Cost > maxCost {
+				maxCost = incoming.Cost + outgoing.Cost
+			}
+		}
+
+		witnesses := c.witnessSearch(from, state, maxCost)
+		for to, outgoing := range c.out[state] {
+			if to == from {
+				continue
+			}
+
+			cost := incoming.Cost + outgoing.Cost
+			if witness, ok := witnesses[to]; ok && witness <= cost {
+				continue
+			}
+			out = append(out, Edge{From: from, To: to, Cost: cost, Via: state})
+		}
+	}
+	return out
+}
+
+// witnessSearch runs Dijkstra's algorithm from the source
+// while ignoring the state being contracted, returning the
+// distances to the states it reached within the max cost
+func (c *contractor) witnessSearch(source, ignored int, maxCost float64) map[int]float64 {
+	distances := map[int]float64{source: 0}
+
+	queue := &priorityQueue{{state: source}}
+	for settled := 0; queue.Len() > 0 && settled < witnessSearchLimit; settled++ {
+		current := heap.Pop(queue).(queuedState)
+		if current.priority > distances[current.state] {
+			continue // already found a shorter route
+		}
+		if current.priority > maxCost {
+			break
+		}
+
+		for to, edge := range c.out[current.state] {
+			if to == ignored {
+				continue
+			}
+
+			distance := current.priority + edge.Cost
+			if previous, seen := distances[to]; seen && previous <= distance {
+				continue
+			}
+			distances[to] = distance
+			heap.Push(queue, queuedState{state: to, priority: distance})
+		}
+	}
+	return distances
+}
+
+// remove removes the contracted state from the graph
+func (c *contractor) remove(state int) {
+	for to := range c.out[state] {
+		delete(c.in[to], state)
+		c.contractedNeighbors[to]++
+	}
+	for from := range c.in[state] {
+		delete(c.out[from], state)
+		c.contractedNeighbors[from]++
+	}
+	c.out[state] = nil
+	c.in[state] = nil
+}
+
+type queuedState struct {
+	state    int
+	priority float64
+}
+
+// priorityQueue implements heap.Interface, popping
+// the state with the lowest priority first
+type priorityQueue []queuedState
+
+func (q priorityQueue) Len() int            { return len(q) }
+func (q priorityQueue) Less(i, j int) bool  { return q[i].priority < q[j].priority }
+func (q priorityQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *priorityQueue) Push(x interface{}) { *q = append(*q, x.(queuedState)) }
+func (q *priorityQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	popped := old[n-1]
+	*q = old[:n-1]
+	return popped
+}
@@ -0,0 +1,125 @@
+package ch
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+// Route is the answer to a query
+type Route struct {
+	Cost float64
+
+	// States contains the name of every state
+	// on the route, from the start to the goal
+	States []string
+
+	// Settled is the number of states settled by
+	// the forward and backward searches
+	Settled int
+}
+
+// Query finds the shortest route between the states by
+// searching upwards from the start and backwards upwards
+// from the goal, then unpacking the shortcuts used
+func (h *Hierarchy) Query(from, to string) (Route, error) {
+	start, ok := h.indexes[from]
+	if !ok {
+		return Route{}, fmt.Errorf("start state %s missing from hierarchy", from)
+	}
+	goal, ok := h.indexes[to]
+	if !ok {
+		return Route{}, fmt.Errorf("goal state %s missing from hierarchy", to)
+	}
+
+	forward := h.search(start, false)
+	backward := h.search(goal, true)
+
+	meeting, best := -1, 0.0
+	for state, distance := range forward.distances {
+		if other, ok := backward.distances[state]; ok && (meeting == -1 || distance+other < best) {
+			meeting, best = state, distance+other
+		}
+	}
+
+	route := Route{
+		Cost:    best,
+		Settled: forward.settled + backward.settled,
+	}
+	if meeting == -1 {
+		return route, fmt.Errorf("there is no route from %s to %s", from, to)
+	}
+
+	// walk back to the start, then forward to the goal
+	var edges []Edge
+	for state := meeting; state != start; state = forward.previous[state].From {
+		edges = append([]Edge{forward.previous[state]}, edges...)
+	}
+	for state := meeting; state != goal; state = backward.previous[state].To {
+		edges = append(edges, backward.previous[state])
+	}
+
+	route.States = []string{from}
+	for _, edge := range edges {
+		for _, state := range h.unpack(edge) {
+			route.States = append(route.States, h.Names[state])
+		}
+	}
+	return route, nil
+}
+
+// searchResult contains the states reached by a search
+type searchResult struct {
+	distances map[int]float64
+
+	// previous contains the edge each state was reached
+	// by, which is leaving it for backward searches
+	previous map[int]Edge
+
+	settled int
+}
+
+// search runs Dijkstra's algorithm on the upward edges from
+// the source, or backwards on the downward edges if backward
+func (h *Hierarchy) search(source int, backward bool) searchResult {
+	result := searchResult{
+		distances: map[int]float64{source: 0},
+		previous:  make(map[int]Edge, 64),
+	}
+
+	queue := &priorityQueue{{state: source}}
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(queuedState)
+		if current.priority > result.distances[current.state] {
+			continue // already found a shorter route
+		}
+		result.settled++
+
+		edges, next := h.upward[current.state], func(e Edge) int { return e.To }
+		if backward {
+			edges, next = h.downward[current.state], func(e Edge) int { return e.From }
+		}
+
+		for _, edge := range edges {
+			distance := current.priority + edge.Cost
+			if previous, seen := result.distances[next(edge)]; seen && previous <= distance {
+				continue
+			}
+			result.distances[next(edge)] = distance
+			result.previous[next(edge)] = edge
+			heap.Push(queue, queuedState{state: next(edge), priority: distance})
+		}
+	}
+	return result
+}
+
+// unpack returns the states the edge moves through,
+// including where it ends but not where it starts
+func (h *Hierarchy) unpack(edge Edge) []int {
+	if edge.Via == -1 {
+		return []int{edge.To}
+	}
+
+	first := h.cheapest[[2]int{edge.From, edge.Via}]
+	second := h.cheapest[[2]int{edge.Via, edge.To}]
+	return append(h.unpack(first), h.unpack(second)...)
+}
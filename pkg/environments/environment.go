@@ -1,5 +1,13 @@
 package environments
 
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
 // Environment defines a starting node and a goal node
 type Environment interface {
 	// GoalNodeName returns the name of the goal node
@@ -47,3 +55,281 @@ type Node interface {
 	// IsNode returns if nodes are equivalent
 	IsNode(Node) bool
 }
+
+// NodeKey identifies a node without formatting its
+// name, and is comparable so it can key maps
+type NodeKey struct {
+	keyed bool
+	high  uint64
+	low   uint64
+	name  string
+}
+
+// Key returns a node key made of two numbers,
+// e.g. a packed position and a time
+func Key(high, low uint64) NodeKey {
+	return NodeKey{keyed: true, high: high, low: low}
+}
+
+// Hash returns a hash of the key, e.g. to
+// spread nodes between workers
+func (k NodeKey) Hash() uint64 {
+	if !k.keyed {
+		hash := fnv.New64a()
+		hash.Write([]byte(k.name))
+		return hash.Sum64()
+	}
+
+	// mix the numbers (from splitmix64), so
+	// nearby points don't hash to nearby values
+	hash := k.high*0x9e3779b97f4a7c15 ^ k.low
+	hash = (hash ^ hash>>30) * 0xbf58476d1ce4e5b9
+	hash = (hash ^ hash>>27) * 0x94d049bb133111eb
+	return hash ^ hash>>31
+}
+
+// NameKey returns the node key of a name, for
+// nodes that can't be packed into numbers
+func NameKey(name string) NodeKey {
+	return NodeKey{name: name}
+}
+
+// MarshalText encodes the key, so it can be
+// saved (e.g. as a key of a JSON object)
+func (k NodeKey) MarshalText() ([]byte, error) {
+	if k.keyed {
+		return []byte("k:" + strconv.FormatUint(k.high, 16) + ":" + strconv.FormatUint(k.low, 16)), nil
+	}
+	return []byte("n:" + k.name), nil
+}
+
+// UnmarshalText decodes a key encoded by MarshalText
+func (k *NodeKey) UnmarshalText(text []byte) error {
+	encoded := string(text)
+	if strings.HasPrefix(encoded, "n:") {
+		*k = NameKey(encoded[2:])
+		return nil
+	}
+
+	parts := strings.Split(encoded, ":")
+	if len(parts) != 3 || parts[0] != "k" {
+		return fmt.Errorf("could not decode node key %s", encoded)
+	}
+	high, err := strconv.ParseUint(parts[1], 16, 64)
+	if err != nil {
+		return fmt.Errorf("could not decode node key %s: %w", encoded, err)
+	}
+	low, err := strconv.ParseUint(parts[2], 16, 64)
+	if err != nil {
+		return fmt.Errorf("could not decode node key %s: %w", encoded, err)
+	}
+	*k = Key(high, low)
+	return nil
+}
+
+// MarshalBinary encodes the key more compactly than
+// MarshalText, e.g. to store many keys on disk. The
+// encoded keys sort the same way every time
+func (k NodeKey) MarshalBinary() ([]byte, error) {
+	if !k.keyed {
+		return append([]byte{0}, k.name...), nil
+	}
+
+	encoded := make([]byte, 17)
+	encoded[0] = 1
+	binary.BigEndian.PutUint64(encoded[1:], k.high)
+	binary.BigEndian.PutUint64(encoded[9:], k.low)
+	return encoded, nil
+}
+
+// UnmarshalBinary decodes a key encoded by MarshalBinary
+func (k *NodeKey) UnmarshalBinary(data []byte) error {
+	switch {
+	case len(data) > 0 && data[0] == 0:
+		*k = NameKey(string(data[1:]))
+	case len(data) == 17 && data[0] == 1:
+		*k = Key(binary.BigEndian.Uint64(data[1:]), binary.BigEndian.Uint64(data[9:]))
+	default:
+		return fmt.Errorf("could not decode node key of %d bytes", len(data))
+	}
+	return nil
+}
+
+// KeyedNode is an optional extension of Node
+// for nodes that can be told apart more cheaply
+// than by their names
+type KeyedNode interface {
+	Node
+
+	// Key returns a key that's the same for nodes
+	// with the same name, and different otherwise
+	Key() NodeKey
+}
+
+// KeyOf returns the key of the node, which
+// is its name unless it is a KeyedNode
+func KeyOf(n Node) NodeKey {
+	if keyed, ok := n.(KeyedNode); ok {
+		return keyed.Key()
+	}
+	return NameKey(n.Name())
+}
+
+// FractionalNode is an optional extension of Node
+// for nodes whose cost or heuristic isn't a whole
+// number, which Cost and Heuristic return rounded
+type FractionalNode interface {
+	Node
+
+	// FractionalCost returns the exact cost of
+	// getting to this node from the parent
+	FractionalCost() float64
+
+	// FractionalHeuristic returns the exact
+	// heuristic value to the goal node
+	FractionalHeuristic() float64
+}
+
+// CostOf returns the cost of the node, which
+// is exact if it is a FractionalNode
+func CostOf(n Node) float64 {
+	if fractional, ok := n.(FractionalNode); ok {
+		return fractional.FractionalCost()
+	}
+	return float64(n.Cost())
+}
+
+// HeuristicOf returns the heuristic of the node,
+// which is exact if it is a FractionalNode
+func HeuristicOf(n Node) float64 {
+	if fractional, ok := n.(FractionalNode); ok {
+		return fractional.FractionalHeuristic()
+	}
+	return float64(n.Heuristic())
+}
+
+// NegativeCostError is returned when a step has a negative
+// cost, which most algorithms can't find cheapest paths with
+type NegativeCostError struct {
+	Parent string
+	Child  string
+	Cost   float64
+}
+
+func (e *NegativeCostError) Error() string {
+	return fmt.Sprintf("step from %s to %s has negative cost %s", e.Parent, e.Child, strconv.FormatFloat(e.Cost, 'f', -1, 64))
+}
+
+// CheckCost returns a *NegativeCostError if
+// the step to the child has a negative cost
+func CheckCost(parent, child Node) error {
+	if cost := CostOf(child); cost < 0 {
+		return &NegativeCostError{Parent: parent.Name(), Child: child.Name(), Cost: cost}
+	}
+	return nil
+}
+
+// EncodableEnvironment is an optional extension of
+// Environment whose nodes can be encoded, so they
+// can be stored somewhere other than memory
+type EncodableEnvironment interface {
+	Environment
+
+	// EncodeNode encodes the node, but not its parents
+	EncodeNode(Node) ([]byte, error)
+
+	// DecodeNode decodes a node encoded by EncodeNode.
+	// It has no parent, but has the same key and children
+	// as the node that was encoded
+	DecodeNode([]byte) (Node, error)
+}
+
+// Outcome is a single possible result of a
+// chance node, alongside the probability of
+// it occurring
+type Outcome struct {
+	Node        Node
+	Probability float64
+}
+
+// ChanceNode is an optional extension of Node
+// for stochastic environments, where taking an
+// action can lead to several different nodes
+type ChanceNode interface {
+	Node
+
+	// Outcomes returns the nodes that can result
+	// from reaching this node, with probabilities
+	// that sum to 1. If no outcomes are returned,
+	// the node is treated as deterministic
+	Outcomes() []Outcome
+}
+
+// AdversaryNode is an optional extension of Node
+// for environments where an opponent, rather than
+// the searching agent, picks some of the children
+type AdversaryNode interface {
+	Node
+
+	// AdversaryToMove returns if the opponent
+	// picks which child is taken from this node
+	AdversaryToMove() bool
+}
+
+// Transition is a single possible result of
+// taking an action in a state of an MDP
+type Transition struct {
+	State       string
+	Probability float64
+	Reward      float64
+}
+
+// MDP is a Markov decision process whose states
+// can all be enumerated, which can be solved for
+// a policy rather than searched for a single path
+type MDP interface {
+	Environment
+
+	// States returns the names of every state
+	States() []string
+
+	// Actions returns the actions that can be taken
+	// in the state, and none if the state is terminal
+	Actions(state string) []string
+
+	// Transitions returns the possible results of
+	// taking the action in the state
+	Transitions(state, action string) []Transition
+
+	// Discount returns the factor future rewards
+	// are multiplied by for every step taken
+	Discount() float64
+
+	// VisualizePolicy prints the policy (key = state,
+	// val = action) to the console
+	VisualizePolicy(policy map[string]string)
+}
+
+// AnyAngleEnvironment is an optional extension of
+// Environment for spaces where nodes can be connected
+// by straight lines when nothing is in the way, so
+// that paths aren't constrained to their children
+type AnyAngleEnvironment interface {
+	Environment
+
+	// LineOfSight returns if a straight line between
+	// the nodes is unobstructed
+	LineOfSight(from, to Node) bool
+
+	// Distance returns the length of a straight
+	// line between the nodes
+	Distance(from, to Node) float64
+
+	// DistanceToGoal returns the length of a straight
+	// line from the node to the goal
+	DistanceToGoal(Node) float64
+
+	// Connect returns the node as if it was reached
+	// from the parent in a straight line
+	Connect(parent, node Node) Node
+}
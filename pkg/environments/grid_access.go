@@ -0,0 +1,74 @@
+package environments
+
+import "fmt"
+
+// NewVector2D returns the vector (x,y)
+func NewVector2D(x, y int) Vector2D {
+	return Vector2D{x: x, y: y}
+}
+
+// X returns the x coordinate
+func (v Vector2D) X() int {
+	return v.x
+}
+
+// Y returns the y coordinate
+func (v Vector2D) Y() int {
+	return v.y
+}
+
+// Size returns the width and height of the grid
+func (g *GridEnvironment) Size() Vector2D {
+	return g.gridSize
+}
+
+// StartPoint returns the point of the start node
+func (g *GridEnvironment) StartPoint() Vector2D {
+	return *g.start
+}
+
+// EndPoint returns the point of the goal node
+func (g *GridEnvironment) EndPoint() Vector2D {
+	return *g.end
+}
+
+// PointCost returns the cost of moving into the
+// point, and false if the point is impassable
+func (g *GridEnvironment) PointCost(pnt Vector2D) (int, bool) {
+	point := g.getPoint(pnt)
+	return point.Cost(), point.Passable()
+}
+
+// PathThrough returns the node reached by moving from the
+// first point through every other point in order, which
+// must all be passable and in a line of sight of the last
+func (g *GridEnvironment) PathThrough(points []Vector2D) (Node, error) {
+	if len(points) == 0 {
+		return nil, fmt.Errorf("path must contain at least one point")
+	}
+
+	var node *GridNode
+	for i, pnt := range points {
+		if !g.passable(pnt) {
+			return nil, fmt.Errorf("point %s of path is impassable", pnt.name())
+		}
+
+		if node == nil {
+			node = g.loadNode(pnt, nil, "start", g)
+			continue
+		}
+
+		if !g.LineOfSight(node, g.loadNode(pnt, nil, "", g)) {
+			return nil, fmt.Errorf("point %d of path %s is not in a line of sight of %s", i, pnt.name(), node.point.name())
+		}
+
+		direction := fmt.Sprintf("to %s", pnt.name())
+		for _, move := range directions {
+			if node.point.Add(move.delta).Equals(pnt) {
+				direction = move.name
+			}
+		}
+		node = g.loadNode(pnt, node, direction, g)
+	}
+	return node, nil
+}
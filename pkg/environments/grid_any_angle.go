@@ -0,0 +1,115 @@
+package environments
+
+import (
+	"fmt"
+	"math"
+)
+
+var _ AnyAngleEnvironment = &GridEnvironment{}
+
+// LineOfSight returns if every point a straight line between
+// the centers of the nodes' points touches is passable. Lines
+// that pass exactly through a corner touch both points beside it
+func (g *GridEnvironment) LineOfSight(from, to Node) bool {
+	fromNode, ok := from.(*GridNode)
+	if !ok {
+		return false
+	}
+	toNode, ok := to.(*GridNode)
+	if !ok {
+		return false
+	}
+
+	for _, pnt := range supercover(fromNode.point, toNode.point) {
+		if !g.passable(pnt) {
+			return false
+		}
+	}
+	return true
+}
+
+// Distance returns the Euclidean distance between the nodes
+func (g *GridEnvironment) Distance(from, to Node) float64 {
+	fromNode, ok := from.(*GridNode)
+	if !ok {
+		return math.Inf(1)
+	}
+	toNode, ok := to.(*GridNode)
+	if !ok {
+		return math.Inf(1)
+	}
+
+	return fromNode.point.EuclideanDistanceTo(toNode.point)
+}
+
+// DistanceToGoal returns the Euclidean distance
+// between the node and the end point
+func (g *GridEnvironment) DistanceToGoal(n Node) float64 {
+	if gridNode, ok := n.(*GridNode); ok {
+		return gridNode.point.EuclideanDistanceTo(*g.end)
+	}
+	return math.Inf(1)
+}
+
+// Connect returns a node at the same point as the node,
+// but with the parent as its parent
+func (g *GridEnvironment) Connect(parent, node Node) Node {
+	parentNode, ok := parent.(*GridNode)
+	if !ok {
+		return node
+	}
+	gridNode, ok := node.(*GridNode)
+	if !ok {
+		return node
+	}
+
+	return g.loadNode(gridNode.point, parentNode, fmt.Sprintf("to %s", gridNode.point.name()), g)
+}
+
+// EuclideanDistanceTo calculates the straight line
+// distance to another point
+func (v Vector2D) EuclideanDistanceTo(other Vector2D) float64 {
+	return math.Hypot(float64(other.x-v.x), float64(other.y-v.y))
+}
+
+// supercover returns every point that a straight line
+// between the centers of the points touches, in order
+func supercover(from, to Vector2D) []Vector2D {
+	dx, dy := to.x-from.x, to.y-from.y
+	nx, ny := int(abs(int32(dx))), int(abs(int32(dy)))
+	step := Vector2D{x: sign(dx), y: sign(dy)}
+
+	pnt := from
+	out := make([]Vector2D, 1, nx+ny+1)
+	out[0] = pnt
+	for ix, iy := 0, 0; ix < nx || iy < ny; {
+		// compare how far along the line the next vertical
+		// and horizontal edges are crossed
+		decision := (1+2*ix)*ny - (1+2*iy)*nx
+		if decision == 0 {
+			// passing through a corner touches
+			// the points on either side of it
+			out = append(out, pnt.Add(Vector2D{x: step.x}), pnt.Add(Vector2D{y: step.y}))
+			pnt = pnt.Add(step)
+			ix++
+			iy++
+		} else if decision < 0 {
+			pnt = pnt.Add(Vector2D{x: step.x})
+			ix++
+		} else {
+			pnt = pnt.Add(Vector2D{y: step.y})
+			iy++
+		}
+		out = append(out, pnt)
+	}
+	return out
+}
+
+func sign(n int) int {
+	if n < 0 {
+		return -1
+	} else if n > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,44 @@
+package environments
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+var _ EncodableEnvironment = &GridEnvironment{}
+
+// EncodeNode encodes the point and timestep of the node
+func (g *GridEnvironment) EncodeNode(n Node) ([]byte, error) {
+	gridNode, ok := n.(*GridNode)
+	if !ok {
+		return nil, fmt.Errorf("cannot encode %s, which isn't a grid node", n.Name())
+	}
+
+	encoded := make([]byte, 3*binary.MaxVarintLen64)
+	size := binary.PutVarint(encoded, int64(gridNode.point.x))
+	size += binary.PutVarint(encoded[size:], int64(gridNode.point.y))
+	size += binary.PutVarint(encoded[size:], int64(gridNode.time))
+	return encoded[:size], nil
+}
+
+// DecodeNode decodes a node encoded by EncodeNode
+func (g *GridEnvironment) DecodeNode(data []byte) (Node, error) {
+	var values [3]int
+	for i := range values {
+		value, read := binary.Varint(data)
+		if read <= 0 {
+			return nil, fmt.Errorf("could not decode grid node")
+		}
+		values[i] = int(value)
+		data = data[read:]
+	}
+
+	pnt := Vector2D{x: values[0], y: values[1]}
+	if !pnt.WithinBounds(g.gridSize) {
+		return nil, fmt.Errorf("decoded point %s is outside of the grid", pnt.name())
+	}
+
+	node := g.loadNode(pnt, nil, "", g)
+	node.time = values[2]
+	return node, nil
+}
@@ -2,6 +2,7 @@ package environments
 
 import (
 	"fmt"
+	"math"
 	"strings"
 )
 
@@ -9,23 +10,57 @@ func init() {
 	addEnvironmentType("grid", &GridEnvironment{})
 }
 
+var _ FractionalNode = &GridNode{}
+
 // GridEnvironment is a Grid World environmnet
 // which can be loaded from JSON
 type GridEnvironment struct {
 	GridName string   `json:"grid_name"`
 	Grid     []string `json:"grid"`
 
+	// SlipProbability is the chance of moving
+	// perpendicular to the intended direction
+	// when the grid is solved as an MDP
+	SlipProbability float64 `json:"slip_probability"`
+
+	// DiscountFactor is used when the grid
+	// is solved as an MDP, defaulting to 1
+	DiscountFactor *float64 `json:"discount,omitempty"`
+
+	// Obstacles schedules points that are impassable at
+	// a timestep (key = timestep, val = points as "(x,y)").
+	// Grids with obstacles are searched through time, and
+	// nodes can wait in place
+	Obstacles map[int][]string `json:"obstacles"`
+
+	// ObstaclePeriod repeats the obstacles
+	// every period timesteps, if it is set
+	ObstaclePeriod int `json:"obstacle_period"`
+
 	gridSize Vector2D
 	start    *Vector2D
 	end      *Vector2D
+
+	// blocked contains the points of the
+	// obstacles at each timestep
+	blocked map[int]map[Vector2D]bool
+
+	// lastObstacle is the last timestep with obstacles
+	lastObstacle int
 }
 
 func (g *GridEnvironment) loadNode(pnt Vector2D, parent *GridNode, direction string, env *GridEnvironment) *GridNode {
+	time := 0
+	if parent != nil {
+		time = parent.time + 1
+	}
+
 	return &GridNode{
 		point:     pnt,
 		env:       env,
 		parent:    parent,
 		direction: direction,
+		time:      time,
 	}
 }
 
@@ -43,21 +78,23 @@ func (g *GridEnvironment) Start() Node {
 func (g *GridEnvironment) getNeighbors(node *GridNode) []Node {
 	pnt := node.point
 
-	possiblePoints := map[string]Vector2D{
-		"up":    pnt.Add(Vector2D{y: -1}),
-		"down":  pnt.Add(Vector2D{y: 1}),
-		"left":  pnt.Add(Vector2D{x: -1}),
-		"right": pnt.Add(Vector2D{x: 1}),
-	}
-
 	out := make([]Node, 0)
-	for name, vec := range possiblePoints {
+	for _, direction := range directions {
+		vec := pnt.Add(direction.delta)
 		pnt := g.getPoint(vec)
 		if pnt.Passable() == false {
 			continue
 		}
 
-		out = append(out, g.loadNode(vec, node, name, g))
+		if g.Timed() && g.obstructed(node.point, vec, node.time) {
+			continue
+		}
+
+		out = append(out, g.loadNode(vec, node, direction.name, g))
+	}
+
+	if g.Timed() && !g.obstructed(pnt, pnt, node.time) {
+		out = append(out, g.loadNode(pnt, node, wait, g))
 	}
 
 	return out
@@ -76,21 +113,34 @@ func (g *GridEnvironment) getPoint(pnt Vector2D) gridPoint {
 }
 
 // VisualizeSolution prints out the grid with the path returned
-// by the algorithm
+// by the algorithm, or the grid at every timestep of the path
+// if the grid has obstacles
 func (g *GridEnvironment) VisualizeSolution(n Node) {
+	if g.Timed() {
+		g.visualizeTimesteps(n)
+		return
+	}
+
 	solutionGrid := g.copyGrid()
 
 	if solutionGridNode, ok := n.(*GridNode); ok {
 		parent := solutionGridNode
 		for parent != nil {
-			pnt := parent.point
+			// draw the straight line from the parent, which
+			// is more than one point for any-angle paths
+			line := []Vector2D{parent.point}
+			if parent.parent != nil {
+				line = supercover(parent.parent.point, parent.point)
+			}
 
-			col := solutionGrid[pnt.y]
-			colBytes := []rune(col)
-			colBytes[pnt.x] = rune(Path)
-			col = string(colBytes)
+			for _, pnt := range line {
+				col := solutionGrid[pnt.y]
+				colBytes := []rune(col)
+				colBytes[pnt.x] = rune(Path)
+				col = string(colBytes)
 
-			solutionGrid[pnt.y] = col
+				solutionGrid[pnt.y] = col
+			}
 
 			parent = parent.parent
 		}
@@ -157,13 +207,21 @@ func (g *GridEnvironment) Validate() error {
 		}
 	}
 
+	if g.SlipProbability < 0 || g.SlipProbability > 1 {
+		return fmt.Errorf("slip_probability must be between 0 and 1, but was %f", g.SlipProbability)
+	}
+
+	if err := validateDiscount(g.DiscountFactor); err != nil {
+		return err
+	}
+
 	if g.start == nil {
 		return fmt.Errorf("could not find start point (char:%s)", string(Start))
 	} else if g.end == nil {
 		return fmt.Errorf("could not find end point (char:%s)", string(End))
 	}
 
-	return nil
+	return g.validateObstacles()
 }
 
 // GridNode implements Node with
@@ -175,6 +233,10 @@ type GridNode struct {
 	env       *GridEnvironment
 	parent    *GridNode
 	direction string
+
+	// time is the number of steps taken
+	// since the start
+	time int
 }
 
 // Heuristic returns the Manhattan Distance to the
@@ -188,15 +250,47 @@ func (g *GridNode) Children() []Node {
 	return g.env.getNeighbors(g)
 }
 
-// Name is the point (x,y) on the grid
+// Name is the point (x,y) on the grid, followed by
+// the timestep as (x,y)@t if the grid has obstacles
 func (g *GridNode) Name() string {
-	return fmt.Sprintf("(%d,%d)", g.point.x, g.point.y)
+	if g.env.Timed() {
+		return fmt.Sprintf("%s@%d", g.point.name(), g.env.scheduleTime(g.time))
+	}
+	return g.point.name()
+}
+
+// Key returns the point, and the time in
+// the schedule if the grid has obstacles
+func (g *GridNode) Key() NodeKey {
+	time := 0
+	if g.env.Timed() {
+		time = g.env.scheduleTime(g.time)
+	}
+	return Key(uint64(uint32(g.point.x))<<32|uint64(uint32(g.point.y)), uint64(time))
 }
 
 // Cost is the cost of movement, according
-// to the map
+// to the map, rounded if the node is connected
+// to its parent in a straight line
 func (g *GridNode) Cost() int {
-	return g.env.getPoint(g.point).Cost()
+	return int(math.Round(g.FractionalCost()))
+}
+
+// FractionalCost is the cost of the point moved into,
+// multiplied by the Euclidean length of the straight
+// line from the parent if it isn't a neighbor
+func (g *GridNode) FractionalCost() float64 {
+	cost := float64(g.env.getPoint(g.point).Cost())
+	if g.parent == nil || g.parent.point.ManhattanDistanceTo(g.point) <= 1 {
+		return cost
+	}
+	return cost * g.parent.point.EuclideanDistanceTo(g.point)
+}
+
+// FractionalHeuristic returns the heuristic,
+// which is always a whole number
+func (g *GridNode) FractionalHeuristic() float64 {
+	return float64(g.Heuristic())
 }
 
 // Steps traverses through the parents
@@ -216,14 +310,14 @@ func (g *GridNode) Steps() []string {
 	return names
 }
 
-// IsNode checks equality with another node
-// by checking the x/y positions
+// IsNode checks equality with another node by checking
+// the x/y positions (and timesteps, with obstacles)
 func (g *GridNode) IsNode(other Node) bool {
 	if otherGridNode, ok := other.(*GridNode); ok {
 		if otherGridNode == nil || g == nil {
 			return false
 		}
-		return otherGridNode.point.Equals(g.point)
+		return otherGridNode.Key() == g.Key()
 	}
 	return false
 }
@@ -300,6 +394,15 @@ const (
 )
 
 var (
+	// directions are in a fixed order, so children are
+	// always generated (and tie broken) in the same order
+	directions = []direction{
+		{name: "up", delta: Vector2D{y: -1}},
+		{name: "down", delta: Vector2D{y: 1}},
+		{name: "left", delta: Vector2D{x: -1}},
+		{name: "right", delta: Vector2D{x: 1}},
+	}
+
 	valid = []gridPoint{
 		Start,
 		End,
@@ -344,3 +447,19 @@ func abs(n int32) int32 {
 	y := n >> 31
 	return (n ^ y) - y
 }
+
+// direction is a move to a neighboring point
+type direction struct {
+	name  string
+	delta Vector2D
+}
+
+// directionDelta returns the move of the named direction
+func directionDelta(name string) (Vector2D, bool) {
+	for _, direction := range directions {
+		if direction.name == name {
+			return direction.delta, true
+		}
+	}
+	return Vector2D{}, false
+}
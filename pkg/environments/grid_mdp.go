@@ -0,0 +1,134 @@
+package environments
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var _ MDP = &GridEnvironment{}
+
+var (
+	// perpendicular defines the directions a move
+	// can slip into on a slippery grid
+	perpendicular = map[string][2]string{
+		"up":    {"left", "right"},
+		"down":  {"left", "right"},
+		"left":  {"up", "down"},
+		"right": {"up", "down"},
+	}
+
+	policyArrows = map[string]rune{
+		"up":    '^',
+		"down":  'v',
+		"left":  '<',
+		"right": '>',
+	}
+)
+
+// States returns the names of every passable point
+func (g *GridEnvironment) States() []string {
+	out := make([]string, 0, g.gridSize.x*g.gridSize.y)
+	for y := 0; y < g.gridSize.y; y++ {
+		for x := 0; x < g.gridSize.x; x++ {
+			pnt := Vector2D{x: x, y: y}
+			if g.passable(pnt) {
+				out = append(out, pnt.name())
+			}
+		}
+	}
+	return out
+}
+
+// Actions returns up/down/left/right for every
+// point except the end point
+func (g *GridEnvironment) Actions(state string) []string {
+	pnt, err := parsePointName(state)
+	if err != nil || pnt.Equals(*g.end) {
+		return nil
+	}
+
+	out := make([]string, 0, len(directions))
+	for _, direction := range directions {
+		out = append(out, direction.name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Transitions returns the intended move, and the moves
+// perpendicular to it split evenly by the slip probability.
+// Moves into impassable points stay in place. The reward
+// is the negative cost of the point moved into
+func (g *GridEnvironment) Transitions(state, action string) []Transition {
+	pnt, err := parsePointName(state)
+	if err != nil {
+		return nil
+	}
+
+	probabilities := make(map[Vector2D]float64, 3)
+	probabilities[g.move(pnt, action)] += 1 - g.SlipProbability
+	for _, slip := range perpendicular[action] {
+		probabilities[g.move(pnt, slip)] += g.SlipProbability / 2
+	}
+
+	out := make([]Transition, 0, len(probabilities))
+	for next, probability := range probabilities {
+		if probability == 0 {
+			continue
+		}
+		out = append(out, Transition{
+			State:       next.name(),
+			Probability: probability,
+			Reward:      -float64(g.getPoint(next).Cost()),
+		})
+	}
+	return out
+}
+
+// move returns the point reached when moving in the
+// direction, which is the same point if it is blocked
+func (g *GridEnvironment) move(pnt Vector2D, direction string) Vector2D {
+	delta, _ := directionDelta(direction)
+	next := pnt.Add(delta)
+	if !g.passable(next) {
+		return pnt
+	}
+	return next
+}
+
+// Discount returns the discount factor
+func (g *GridEnvironment) Discount() float64 {
+	return discountOf(g.DiscountFactor)
+}
+
+// VisualizePolicy prints out the grid with an arrow
+// for the action taken at each point
+func (g *GridEnvironment) VisualizePolicy(policy map[string]string) {
+	policyGrid := make([]string, len(g.Grid))
+	for y, row := range g.Grid {
+		runes := []rune(row)
+		for x := range runes {
+			if arrow, ok := policyArrows[policy[Vector2D{x: x, y: y}.name()]]; ok {
+				runes[x] = arrow
+			}
+		}
+		policyGrid[y] = string(runes)
+	}
+
+	fmt.Println(strings.Join(policyGrid, "\n"))
+}
+
+// name returns the name of the point, (x,y)
+func (v Vector2D) name() string {
+	return fmt.Sprintf("(%d,%d)", v.x, v.y)
+}
+
+// parsePointName parses a point from its name
+func parsePointName(name string) (Vector2D, error) {
+	pnt := Vector2D{}
+	if _, err := fmt.Sscanf(name, "(%d,%d)", &pnt.x, &pnt.y); err != nil {
+		return Vector2D{}, fmt.Errorf("could not parse point %s: %w", name, err)
+	}
+	return pnt, nil
+}
@@ -0,0 +1,116 @@
+package environments
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Obstacle is how scheduled obstacles are drawn
+const Obstacle gridPoint = 'o'
+
+// Timed checks if the grid has scheduled obstacles,
+// so it has to be searched through time
+func (g *GridEnvironment) Timed() bool {
+	return len(g.Obstacles) > 0
+}
+
+// scheduleTime returns the earliest timestep with the same
+// obstacles as the time, which is in the first period if the
+// obstacles repeat, or else no later than just after the last
+// obstacle. Nodes at the same point and schedule time are the
+// same, which keeps the number of nodes finite
+func (g *GridEnvironment) scheduleTime(time int) int {
+	if g.ObstaclePeriod > 0 {
+		return time % g.ObstaclePeriod
+	}
+	if time > g.lastObstacle {
+		return g.lastObstacle + 1
+	}
+	return time
+}
+
+// blockedAt checks if an obstacle is on the point at the time
+func (g *GridEnvironment) blockedAt(pnt Vector2D, time int) bool {
+	return g.blocked[g.scheduleTime(time)][pnt]
+}
+
+// obstructed checks if moving from one point to the other
+// after the time runs into an obstacle, either by ending on
+// the same point or by swapping points with it
+func (g *GridEnvironment) obstructed(from, to Vector2D, time int) bool {
+	if g.blockedAt(to, time+1) {
+		return true
+	}
+	return !from.Equals(to) && g.blockedAt(to, time) && g.blockedAt(from, time+1)
+}
+
+// validateObstacles checks that the obstacles are on
+// passable points within the grid, and not on the start
+// point at the start
+func (g *GridEnvironment) validateObstacles() error {
+	if g.ObstaclePeriod < 0 {
+		return fmt.Errorf("obstacle_period must not be negative, but was %d", g.ObstaclePeriod)
+	}
+
+	g.blocked = make(map[int]map[Vector2D]bool, len(g.Obstacles))
+	g.lastObstacle = 0
+	for time, points := range g.Obstacles {
+		if time < 0 {
+			return fmt.Errorf("obstacles at timestep %d must not be at a negative timestep", time)
+		}
+		if g.ObstaclePeriod > 0 && time >= g.ObstaclePeriod {
+			return fmt.Errorf("obstacles at timestep %d must be before the obstacle_period %d", time, g.ObstaclePeriod)
+		}
+
+		g.blocked[time] = make(map[Vector2D]bool, len(points))
+		for _, name := range points {
+			pnt, err := g.waypoint(name)
+			if err != nil {
+				return fmt.Errorf("obstacle at timestep %d: %w", time, err)
+			}
+			g.blocked[time][pnt] = true
+		}
+
+		if time > g.lastObstacle {
+			g.lastObstacle = time
+		}
+	}
+
+	if g.Timed() && g.blockedAt(*g.start, 0) {
+		return fmt.Errorf("obstacle on start point %s at timestep 0", g.start.name())
+	}
+
+	return nil
+}
+
+// visualizeTimesteps prints out the grid at every timestep
+// of the path, with the obstacles at that timestep
+func (g *GridEnvironment) visualizeTimesteps(n Node) {
+	gridNode, ok := n.(*GridNode)
+	if !ok {
+		return
+	}
+
+	timesteps := make([]*GridNode, 0, gridNode.time+1)
+	for parent := gridNode; parent != nil; parent = parent.parent {
+		timesteps = append(timesteps, parent)
+	}
+
+	for i := len(timesteps) - 1; i >= 0; i-- {
+		node := timesteps[i]
+		fmt.Printf("Time %d:\n", node.time)
+
+		grid := g.copyGrid()
+		for pnt := range g.blocked[g.scheduleTime(node.time)] {
+			row := []rune(grid[pnt.y])
+			row[pnt.x] = rune(Obstacle)
+			grid[pnt.y] = string(row)
+		}
+
+		row := []rune(grid[node.point.y])
+		row[node.point.x] = rune(Path)
+		grid[node.point.y] = string(row)
+
+		fmt.Println(strings.Join(grid, "\n"))
+	}
+}
@@ -0,0 +1,164 @@
+package environments
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+func init() {
+	addEnvironmentType("mdp", &MDPEnvironment{})
+}
+
+var _ MDP = &MDPEnvironment{}
+
+// MDPEnvironment is a StateEnvironment where every
+// state can also define actions with probabilistic
+// transitions and rewards, loaded from a json file
+type MDPEnvironment struct {
+	StateEnvironment
+
+	// MDPStates shadows the states of the
+	// StateEnvironment so that actions can be loaded
+	MDPStates MDPStates `json:"states"`
+
+	// DiscountFactor defaults to 1 if not supplied
+	DiscountFactor *float64 `json:"discount,omitempty"`
+}
+
+// Validate checks if the environment is valid, both
+// as a StateEnvironment and as an MDP
+func (m *MDPEnvironment) Validate() error {
+	m.StateEnvironment.States = make(States, len(m.MDPStates))
+	for name, state := range m.MDPStates {
+		m.StateEnvironment.States[name] = state.State
+	}
+
+	// a *NegativeCostError is returned last,
+	// after the MDP is validated as well
+	stateErr := m.StateEnvironment.Validate()
+	var negativeCost *NegativeCostError
+	if stateErr != nil && !errors.As(stateErr, &negativeCost) {
+		return stateErr
+	}
+
+	if err := validateDiscount(m.DiscountFactor); err != nil {
+		return err
+	}
+
+	for stateName, state := range m.MDPStates {
+		for actionName, action := range state.Actions {
+			if len(action.Transitions) == 0 {
+				return fmt.Errorf("action %s of %s has no transitions", actionName, stateName)
+			}
+
+			total := 0.0
+			for nextName, probability := range action.Transitions {
+				if _, ok := m.MDPStates[nextName]; !ok {
+					return fmt.Errorf("missing state %s in states (from action %s of %s)", nextName, actionName, stateName)
+				}
+				if probability < 0 {
+					return fmt.Errorf("transition to %s from action %s of %s has negative probability %f", nextName, actionName, stateName, probability)
+				}
+				total += probability
+			}
+
+			if math.Abs(total-1) > probabilityTolerance {
+				return fmt.Errorf("transition probabilities of action %s of %s sum to %f instead of 1", actionName, stateName, total)
+			}
+		}
+	}
+
+	return stateErr
+}
+
+// States returns the names of every state, sorted
+func (m *MDPEnvironment) States() []string {
+	names := make([]string, 0, len(m.MDPStates))
+	for name := range m.MDPStates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Actions returns the sorted actions of the state,
+// and none for the goal state
+func (m *MDPEnvironment) Actions(state string) []string {
+	if state == m.GoalNode {
+		return nil
+	}
+
+	actions := m.MDPStates[state].Actions
+	names := make([]string, 0, len(actions))
+	for name := range actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Transitions returns the possible next states after
+// taking the action, all with the action's reward
+func (m *MDPEnvironment) Transitions(state, action string) []Transition {
+	mdpAction := m.MDPStates[state].Actions[action]
+
+	out := make([]Transition, 0, len(mdpAction.Transitions))
+	for nextName, probability := range mdpAction.Transitions {
+		out = append(out, Transition{
+			State:       nextName,
+			Probability: probability,
+			Reward:      mdpAction.Reward,
+		})
+	}
+	return out
+}
+
+// Discount returns the discount factor
+func (m *MDPEnvironment) Discount() float64 {
+	return discountOf(m.DiscountFactor)
+}
+
+// validateDiscount checks that the discount factor, if
+// it was supplied, is greater than 0 and at most 1
+func validateDiscount(factor *float64) error {
+	if factor != nil && (*factor <= 0 || *factor > 1) {
+		return fmt.Errorf("discount must be greater than 0 and at most 1, but was %f", *factor)
+	}
+	return nil
+}
+
+// discountOf returns the discount
+// factor, or 1 if it wasn't supplied
+func discountOf(factor *float64) float64 {
+	if factor == nil {
+		return 1
+	}
+	return *factor
+}
+
+// VisualizePolicy does nothing, as states have no
+// layout to draw the policy on
+func (m *MDPEnvironment) VisualizePolicy(policy map[string]string) {
+}
+
+// MDPStates contains all of the states of an MDP environment
+type MDPStates map[string]MDPState
+
+// MDPState is the json supplied to encode a state
+// of an MDP environment
+type MDPState struct {
+	State
+
+	Actions map[string]MDPAction `json:"actions"`
+}
+
+// MDPAction is the json supplied to encode an
+// action that can be taken in an MDP state
+type MDPAction struct {
+	// Transitions contains the possible next states
+	// (key = name, val = probability)
+	Transitions map[string]float64 `json:"transitions"`
+	Reward      float64            `json:"reward"`
+}
@@ -0,0 +1,439 @@
+package environments
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+func init() {
+	addEnvironmentType("multi_agent_grid", &MultiAgentGridEnvironment{})
+}
+
+var _ Environment = &MultiAgentGridEnvironment{}
+var _ Node = &MultiAgentNode{}
+
+// wait is the action of staying on the same point
+const wait = "wait"
+
+// MultiAgentGridEnvironment is a grid shared by several agents,
+// which each move from their start point to their goal point.
+// Every timestep each agent moves up/down/left/right or waits,
+// and no two agents can be on the same point at the same time
+// (a vertex conflict) or swap points (an edge conflict).
+//
+// Searching it directly searches the joint positions of every
+// agent, costing 1 per timestep for every agent that isn't
+// waiting at its goal. The cost of the points on the grid
+// is ignored, as moving always takes one timestep
+type MultiAgentGridEnvironment struct {
+	GridName string   `json:"grid_name"`
+	Grid     []string `json:"grid"`
+
+	// Agents contains the start and goal of every agent
+	Agents []Agent `json:"agents"`
+
+	grid   *GridEnvironment
+	starts []Vector2D
+	goals  []Vector2D
+}
+
+// Agent is an agent moving across a
+// multi-agent grid, with points as "(x,y)"
+type Agent struct {
+	Name  string `json:"name"`
+	Start string `json:"start"`
+	Goal  string `json:"goal"`
+}
+
+// Name returns the name of the grid
+func (m *MultiAgentGridEnvironment) Name() string {
+	return m.GridName
+}
+
+// Start returns the node of every agent at its start
+func (m *MultiAgentGridEnvironment) Start() Node {
+	return &MultiAgentNode{
+		env:       m,
+		positions: m.starts,
+	}
+}
+
+// IsGoalNode checks if every agent is at its goal
+func (m *MultiAgentGridEnvironment) IsGoalNode(n Node) bool {
+	node, ok := n.(*MultiAgentNode)
+	if !ok {
+		return false
+	}
+
+	for i, pnt := range node.positions {
+		if !pnt.Equals(m.goals[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// VisualizeSolution prints out the grid at every timestep,
+// with each agent as a letter, followed by a table of the
+// point each agent is on at every timestep
+func (m *MultiAgentGridEnvironment) VisualizeSolution(n Node) {
+	node, ok := n.(*MultiAgentNode)
+	if !ok {
+		return
+	}
+
+	timesteps := make([]*MultiAgentNode, 0, node.time+1)
+	for parent := node; parent != nil; parent = parent.parent {
+		timesteps = append(timesteps, parent)
+	}
+	for i, j := 0, len(timesteps)-1; i < j; i, j = i+1, j-1 {
+		timesteps[i], timesteps[j] = timesteps[j], timesteps[i]
+	}
+
+	for i, agent := range m.Agents {
+		fmt.Printf("%c: %s\n", agentLetter(i), agent.Name)
+	}
+
+	for _, timestep := range timesteps {
+		fmt.Printf("Time %d:\n", timestep.time)
+
+		grid := m.grid.copyGrid()
+		for i, pnt := range timestep.positions {
+			row := []rune(grid[pnt.y])
+			row[pnt.x] = agentLetter(i)
+			grid[pnt.y] = string(row)
+		}
+		fmt.Println(strings.Join(grid, "\n"))
+	}
+
+	fmt.Println(m.timeTable(timesteps))
+}
+
+// timeTable returns the point each agent is on at
+// every timestep, with a row per agent
+func (m *MultiAgentGridEnvironment) timeTable(timesteps []*MultiAgentNode) string {
+	width := len("time")
+	for _, timestep := range timesteps {
+		for _, pnt := range timestep.positions {
+			if len(pnt.name()) > width {
+				width = len(pnt.name())
+			}
+		}
+	}
+
+	rows := make([]string, len(m.Agents)+1)
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-*s", len(m.longestAgentName())+1, "")
+	for _, timestep := range timesteps {
+		fmt.Fprintf(&b, " %-*d", width, timestep.time)
+	}
+	rows[0] = strings.TrimRight(b.String(), " ")
+
+	for i, agent := range m.Agents {
+		b.Reset()
+		fmt.Fprintf(&b, "%-*s", len(m.longestAgentName())+1, agent.Name+":")
+		for _, timestep := range timesteps {
+			fmt.Fprintf(&b, " %-*s", width, timestep.positions[i].name())
+		}
+		rows[i+1] = strings.TrimRight(b.String(), " ")
+	}
+	return strings.Join(rows, "\n")
+}
+
+func (m *MultiAgentGridEnvironment) longestAgentName() string {
+	longest := ""
+	for _, agent := range m.Agents {
+		if len(agent.Name) > len(longest) {
+			longest = agent.Name
+		}
+	}
+	return longest
+}
+
+// agentLetter returns the letter the agent
+// is drawn as, which is A for the first
+func agentLetter(agent int) rune {
+	return rune('A' + agent%26)
+}
+
+// Validate checks the grid, and that every agent starts
+// and ends on a different passable point
+func (m *MultiAgentGridEnvironment) Validate() error {
+	if len(m.Grid) == 0 {
+		return fmt.Errorf("must supply grid when using type multi_agent_grid")
+	}
+
+	m.grid = &GridEnvironment{
+		GridName: m.GridName,
+		Grid:     m.Grid,
+		gridSize: Vector2D{x: len(m.Grid[0]), y: len(m.Grid)},
+	}
+
+	for y, gridRow := range m.Grid {
+		if len(gridRow) != m.grid.gridSize.x {
+			return fmt.Errorf("expected all rows to have same size (%d), but row %d was of length %d", m.grid.gridSize.x, y, len(gridRow))
+		}
+		for x, char := range gridRow {
+			if !gridPoint(char).Valid() {
+				return fmt.Errorf("point at (%d,%d) had invalid value: %s", x, y, string(char))
+			}
+		}
+	}
+
+	if len(m.Agents) == 0 {
+		return fmt.Errorf("must supply agents when using type multi_agent_grid")
+	}
+
+	m.starts = make([]Vector2D, len(m.Agents))
+	m.goals = make([]Vector2D, len(m.Agents))
+	starts := make(map[Vector2D]string, len(m.Agents))
+	goals := make(map[Vector2D]string, len(m.Agents))
+	for i, agent := range m.Agents {
+		var err error
+		if m.starts[i], err = m.grid.waypoint(agent.Start); err != nil {
+			return fmt.Errorf("start of agent %s: %w", agent.Name, err)
+		}
+		if m.goals[i], err = m.grid.waypoint(agent.Goal); err != nil {
+			return fmt.Errorf("goal of agent %s: %w", agent.Name, err)
+		}
+
+		if other, ok := starts[m.starts[i]]; ok {
+			return fmt.Errorf("agents %s and %s start on the same point %s", other, agent.Name, agent.Start)
+		}
+		if other, ok := goals[m.goals[i]]; ok {
+			return fmt.Errorf("agents %s and %s have the same goal %s", other, agent.Name, agent.Goal)
+		}
+		starts[m.starts[i]] = agent.Name
+		goals[m.goals[i]] = agent.Name
+	}
+
+	return nil
+}
+
+// AgentStart returns the start point of the agent
+func (m *MultiAgentGridEnvironment) AgentStart(agent int) Vector2D {
+	return m.starts[agent]
+}
+
+// AgentGoal returns the goal point of the agent
+func (m *MultiAgentGridEnvironment) AgentGoal(agent int) Vector2D {
+	return m.goals[agent]
+}
+
+// Size returns the width and height of the grid
+func (m *MultiAgentGridEnvironment) Size() Vector2D {
+	return m.grid.gridSize
+}
+
+// Moves returns the points an agent on the point can
+// be on in the next timestep, keyed by the action
+func (m *MultiAgentGridEnvironment) Moves(pnt Vector2D) map[string]Vector2D {
+	out := make(map[string]Vector2D, len(directions)+1)
+	out[wait] = pnt
+	for _, direction := range directions {
+		if next := pnt.Add(direction.delta); m.grid.passable(next) {
+			out[direction.name] = next
+		}
+	}
+	return out
+}
+
+// PathsNode returns the node reached by every agent following
+// its path of points, one per timestep. Agents with shorter
+// paths wait at the end of them
+func (m *MultiAgentGridEnvironment) PathsNode(paths [][]Vector2D) (Node, error) {
+	if len(paths) != len(m.Agents) {
+		return nil, fmt.Errorf("expected %d paths, but got %d", len(m.Agents), len(paths))
+	}
+
+	length := 0
+	for i, path := range paths {
+		if len(path) == 0 || !path[0].Equals(m.starts[i]) {
+			return nil, fmt.Errorf("path of agent %s does not begin at its start", m.Agents[i].Name)
+		}
+		if len(path) > length {
+			length = len(path)
+		}
+	}
+
+	node := m.Start().(*MultiAgentNode)
+	for t := 1; t < length; t++ {
+		positions := make([]Vector2D, len(paths))
+		actions := make([]string, len(paths))
+		for i, path := range paths {
+			previous, pnt := path[len(path)-1], path[len(path)-1]
+			if t < len(path) {
+				previous, pnt = path[t-1], path[t]
+			}
+
+			action := ""
+			for name, next := range m.Moves(previous) {
+				if next.Equals(pnt) {
+					action = name
+				}
+			}
+			if action == "" {
+				return nil, fmt.Errorf("agent %s cannot move from %s to %s", m.Agents[i].Name, previous.name(), pnt.name())
+			}
+
+			positions[i], actions[i] = pnt, action
+		}
+		node = node.load(positions, actions)
+	}
+	return node, nil
+}
+
+// MultiAgentNode is the position of every
+// agent at a timestep
+type MultiAgentNode struct {
+	env       *MultiAgentGridEnvironment
+	parent    *MultiAgentNode
+	positions []Vector2D
+	time      int
+
+	// actions contains the action each
+	// agent took to get here
+	actions []string
+}
+
+func (n *MultiAgentNode) load(positions []Vector2D, actions []string) *MultiAgentNode {
+	return &MultiAgentNode{
+		env:       n.env,
+		parent:    n,
+		positions: positions,
+		time:      n.time + 1,
+		actions:   actions,
+	}
+}
+
+// Name is the point of every agent, in order
+func (n *MultiAgentNode) Name() string {
+	names := make([]string, len(n.positions))
+	for i, pnt := range n.positions {
+		names[i] = pnt.name()
+	}
+	return strings.Join(names, " ")
+}
+
+// Parent returns the parent node, and nil if the start node
+func (n *MultiAgentNode) Parent() Node {
+	if n.parent == nil {
+		return nil // this is required in order to allow nil comparisons
+	}
+	return n.parent
+}
+
+// Children returns every combination of the agents'
+// moves that doesn't have a vertex or edge conflict
+func (n *MultiAgentNode) Children() []Node {
+	moves := make([]map[string]Vector2D, len(n.positions))
+	names := make([][]string, len(n.positions))
+	for i, pnt := range n.positions {
+		moves[i] = n.env.Moves(pnt)
+		for action := range moves[i] {
+			names[i] = append(names[i], action)
+		}
+		sort.Strings(names[i])
+	}
+
+	out := make([]Node, 0, 16)
+	actions := make([]string, len(n.positions))
+	positions := make([]Vector2D, len(n.positions))
+
+	var combine func(agent int)
+	combine = func(agent int) {
+		if agent == len(n.positions) {
+			child := n.load(append([]Vector2D(nil), positions...), append([]string(nil), actions...))
+			out = append(out, child)
+			return
+		}
+
+		for _, action := range names[agent] {
+			pnt := moves[agent][action]
+			if n.conflicts(agent, pnt, positions) {
+				continue
+			}
+			actions[agent], positions[agent] = action, pnt
+			combine(agent + 1)
+		}
+	}
+	combine(0)
+
+	return out
+}
+
+// conflicts checks if the agent moving to the point
+// conflicts with the moves of the agents before it
+func (n *MultiAgentNode) conflicts(agent int, pnt Vector2D, positions []Vector2D) bool {
+	for other := 0; other < agent; other++ {
+		// vertex conflict
+		if positions[other].Equals(pnt) {
+			return true
+		}
+
+		// edge conflict
+		if positions[other].Equals(n.positions[agent]) && n.positions[other].Equals(pnt) {
+			return true
+		}
+	}
+	return false
+}
+
+// Cost is the number of agents that
+// aren't waiting at their goal
+func (n *MultiAgentNode) Cost() int {
+	if n.parent == nil {
+		return 0
+	}
+
+	total := 0
+	for i, action := range n.actions {
+		if action != wait || !n.positions[i].Equals(n.env.goals[i]) {
+			total++
+		}
+	}
+	return total
+}
+
+// Heuristic is the sum of the Manhattan
+// distances of every agent to its goal
+func (n *MultiAgentNode) Heuristic() int {
+	total := 0
+	for i, pnt := range n.positions {
+		total += pnt.ManhattanDistanceTo(n.env.goals[i])
+	}
+	return total
+}
+
+// Steps returns the actions of every
+// agent at every timestep
+func (n *MultiAgentNode) Steps() []string {
+	names := make([]string, 0, n.time+1)
+	for parent := n; parent != nil; parent = parent.parent {
+		if parent.parent == nil {
+			names = append(names, "start")
+			continue
+		}
+
+		actions := make([]string, len(parent.actions))
+		for i, action := range parent.actions {
+			actions[i] = fmt.Sprintf("%c %s", agentLetter(i), action)
+		}
+		names = append(names, strings.Join(actions, " "))
+	}
+	reverse(names)
+	return names
+}
+
+// IsNode checks equality with another node
+// by comparing the points of every agent
+func (n *MultiAgentNode) IsNode(other Node) bool {
+	if otherNode, ok := other.(*MultiAgentNode); ok {
+		if otherNode == nil || n == nil {
+			return false
+		}
+		return otherNode.Name() == n.Name()
+	}
+	return false
+}
@@ -14,9 +14,27 @@ var (
 		"corners": func() (Environment, error) {
 			return LoadEnvironmentFrom(assets.MustOpen("environments/corners.json"))
 		},
+		"crossing": func() (Environment, error) {
+			return LoadEnvironmentFrom(assets.MustOpen("environments/crossing.json"))
+		},
+		"frozen_lake": func() (Environment, error) {
+			return LoadEnvironmentFrom(assets.MustOpen("environments/frozen_lake.json"))
+		},
+		"gamble": func() (Environment, error) {
+			return LoadEnvironmentFrom(assets.MustOpen("environments/gamble.json"))
+		},
 		"maze": func() (Environment, error) {
 			return LoadEnvironmentFrom(assets.MustOpen("environments/maze.json"))
 		},
+		"treasure_hunt": func() (Environment, error) {
+			return LoadEnvironmentFrom(assets.MustOpen("environments/treasure_hunt.json"))
+		},
+		"vacuum_erratic": func() (Environment, error) {
+			return LoadEnvironmentFrom(assets.MustOpen("environments/vacuum_erratic.json"))
+		},
+		"warehouse": func() (Environment, error) {
+			return LoadEnvironmentFrom(assets.MustOpen("environments/warehouse.json"))
+		},
 	}
 )
 
@@ -2,6 +2,8 @@ package environments
 
 import (
 	"fmt"
+	"math"
+	"sort"
 )
 
 func init() {
@@ -10,6 +12,13 @@ func init() {
 
 var _ Environment = &StateEnvironment{}
 var _ Node = &StateNode{}
+var _ ChanceNode = &StateNode{}
+var _ AdversaryNode = &StateNode{}
+var _ FractionalNode = &StateNode{}
+
+// probabilityTolerance is how far outcome probabilities
+// may sum from 1 due to rounding in the JSON
+const probabilityTolerance = 1e-6
 
 // StateEnvironment loads a static environment
 // from a json file
@@ -18,6 +27,16 @@ type StateEnvironment struct {
 	GoalNode        string `json:"goal_node"`
 	States          States `json:"states"`
 	EnvironmentName string `json:"environment_name"`
+
+	// heuristic overrides the heuristic of
+	// the states if it is set
+	heuristic func(state string) float64
+}
+
+// UseHeuristic replaces the heuristic of every state
+// (from the json) with the provided function
+func (l *StateEnvironment) UseHeuristic(heuristic func(state string) float64) {
+	l.heuristic = heuristic
 }
 
 // Start returns the start node
@@ -35,12 +54,34 @@ func (l *StateEnvironment) Name() string {
 	return l.EnvironmentName
 }
 
+var _ EncodableEnvironment = &StateEnvironment{}
+
+// EncodeNode encodes the name of the node's state
+func (l *StateEnvironment) EncodeNode(n Node) ([]byte, error) {
+	stateNode, ok := n.(*StateNode)
+	if !ok {
+		return nil, fmt.Errorf("cannot encode %s, which isn't a state node", n.Name())
+	}
+	return []byte(stateNode.name), nil
+}
+
+// DecodeNode decodes a node encoded by EncodeNode
+func (l *StateEnvironment) DecodeNode(data []byte) (Node, error) {
+	name := string(data)
+	if _, ok := l.States[name]; !ok {
+		return nil, fmt.Errorf("decoded state %s doesn't exist", name)
+	}
+	return l.States.loadNode(name, 0, nil, l), nil
+}
+
 // VisualizeSolution prints out the steps it took
 func (l *StateEnvironment) VisualizeSolution(solution Node) {
 }
 
 // Validate checks if the environment is valid
-// (e.g. all child states resolve to a real state)
+// (e.g. all child states resolve to a real state).
+// Negative costs are checked last, and returned as
+// a *NegativeCostError
 func (l *StateEnvironment) Validate() error {
 	if _, ok := l.States[l.StartNode]; !ok {
 		return fmt.Errorf("start state %s missing from states", l.StartNode)
@@ -57,6 +98,50 @@ func (l *StateEnvironment) Validate() error {
 				return fmt.Errorf("missing child %s in states (from parent %s)", childName, parentName)
 			}
 		}
+
+		if len(state.Outcomes) == 0 {
+			continue
+		}
+
+		total := 0.0
+		for outcomeName, probability := range state.Outcomes {
+			if _, ok := l.States[outcomeName]; !ok {
+				return fmt.Errorf("missing outcome %s in states (from parent %s)", outcomeName, parentName)
+			}
+			if probability < 0 {
+				return fmt.Errorf("outcome %s of %s has negative probability %f", outcomeName, parentName, probability)
+			}
+			total += probability
+		}
+
+		if math.Abs(total-1) > probabilityTolerance {
+			return fmt.Errorf("outcome probabilities of %s sum to %f instead of 1", parentName, total)
+		}
+	}
+
+	// negative costs are checked last, so the algorithms
+	// that support them can ignore the *NegativeCostError
+	// knowing the rest of the environment is valid
+	parentNames := make([]string, 0, len(l.States))
+	for parentName := range l.States {
+		parentNames = append(parentNames, parentName)
+	}
+	sort.Strings(parentNames)
+
+	for _, parentName := range parentNames {
+		state := l.States[parentName]
+
+		childNames := make([]string, 0, len(state.Children))
+		for childName := range state.Children {
+			childNames = append(childNames, childName)
+		}
+		sort.Strings(childNames)
+
+		for _, childName := range childNames {
+			if cost := state.Children[childName]; cost < 0 {
+				return &NegativeCostError{Parent: parentName, Child: childName, Cost: cost}
+			}
+		}
 	}
 
 	return nil
@@ -68,7 +153,7 @@ type StateNode struct {
 	env    *StateEnvironment
 	name   string
 	parent *StateNode
-	cost   int
+	cost   float64
 }
 
 // Steps returns the states it traversed to get to the goal node
@@ -94,12 +179,9 @@ func (n *StateNode) Name() string {
 // node
 func (n *StateNode) Children() []Node {
 	currentState := n.env.States[n.name]
-	out := make([]Node, len(currentState.Children))
-
-	i := 0
-	for childName, cost := range currentState.Children {
-		out[i] = n.env.States.loadNode(childName, cost, n, n.env)
-		i++
+	out := make([]Node, 0, len(currentState.Children))
+	for _, childName := range currentState.childNames() {
+		out = append(out, n.env.States.loadNode(childName, currentState.Children[childName], n, n.env))
 	}
 
 	return out
@@ -113,8 +195,14 @@ func (n *StateNode) Parent() Node {
 	return n.parent
 }
 
-// Cost returns the cost of the node
+// Cost returns the cost of the node,
+// rounded if it isn't a whole number
 func (n *StateNode) Cost() int {
+	return int(math.Round(n.cost))
+}
+
+// FractionalCost returns the exact cost of the node
+func (n *StateNode) FractionalCost() float64 {
 	return n.cost
 }
 
@@ -122,11 +210,45 @@ func (n *StateNode) state() State {
 	return n.env.States[n.name]
 }
 
-// Heuristic returns the node's heuristic value
+// Heuristic returns the node's heuristic value,
+// rounded if it isn't a whole number
 func (n *StateNode) Heuristic() int {
+	return int(math.Round(n.FractionalHeuristic()))
+}
+
+// FractionalHeuristic returns the node's exact heuristic value
+func (n *StateNode) FractionalHeuristic() float64 {
+	if n.env.heuristic != nil {
+		return n.env.heuristic(n.name)
+	}
 	return n.state().Heuristic
 }
 
+// Outcomes returns the states this node can randomly
+// end up in, if it is a chance state, sorted by name
+func (n *StateNode) Outcomes() []Outcome {
+	currentState := n.state()
+	if len(currentState.Outcomes) == 0 {
+		return nil
+	}
+
+	out := make([]Outcome, 0, len(currentState.Outcomes))
+	for _, outcomeName := range currentState.outcomeNames() {
+		out = append(out, Outcome{
+			Node:        n.env.States.loadNode(outcomeName, 0, n, n.env),
+			Probability: currentState.Outcomes[outcomeName],
+		})
+	}
+
+	return out
+}
+
+// AdversaryToMove returns if the state is
+// marked as being controlled by an adversary
+func (n *StateNode) AdversaryToMove() bool {
+	return n.state().Adversary
+}
+
 // IsNode compares equality with another node
 func (n *StateNode) IsNode(other Node) bool {
 	if other == nil || n == nil {
@@ -145,7 +267,7 @@ func (n *StateNode) IsNode(other Node) bool {
 // States contains all of the states of the environment
 type States map[string]State
 
-func (s States) loadNode(name string, cost int, parent *StateNode, env *StateEnvironment) *StateNode {
+func (s States) loadNode(name string, cost float64, parent *StateNode, env *StateEnvironment) *StateNode {
 	return &StateNode{
 		env:    env,
 		name:   name,
@@ -154,8 +276,40 @@ func (s States) loadNode(name string, cost int, parent *StateNode, env *StateEnv
 	}
 }
 
-// State is the json supplied to encode a state
+// State is the json supplied to encode a state. The costs
+// of its children and its heuristic can be fractional
 type State struct {
-	Children  map[string]int `json:"children"`
-	Heuristic int            `json:"heuristic"`
+	Children  map[string]float64 `json:"children"`
+	Heuristic float64            `json:"heuristic"`
+
+	// Outcomes makes this a chance state: once reached,
+	// the environment moves to one of these states
+	// (key = name, val = probability) at no extra cost
+	Outcomes map[string]float64 `json:"outcomes,omitempty"`
+
+	// Adversary marks that an opponent picks
+	// which child is taken from this state
+	Adversary bool `json:"adversary,omitempty"`
+}
+
+// childNames returns the names of the children in
+// order, so they're always generated in the same order
+func (s State) childNames() []string {
+	names := make([]string, 0, len(s.Children))
+	for name := range s.Children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// outcomeNames returns the names of the outcomes in
+// order, so ties between them are always broken the same way
+func (s State) outcomeNames() []string {
+	names := make([]string, 0, len(s.Outcomes))
+	for name := range s.Outcomes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
@@ -0,0 +1,288 @@
+package environments
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	addEnvironmentType("vacuum", &VacuumEnvironment{})
+}
+
+var _ Environment = &VacuumEnvironment{}
+var _ ChanceNode = &VacuumNode{}
+
+// VacuumEnvironment is the vacuum world from AI: A Modern
+// Approach: a row of squares that can be dirty, and a
+// vacuum that can move left, move right, or suck up dirt.
+// The goal is for every square to be clean
+type VacuumEnvironment struct {
+	EnvironmentName string `json:"environment_name"`
+
+	// Position is the square the vacuum starts on
+	Position int `json:"position"`
+
+	// Dirty contains if each square starts dirty
+	Dirty []bool `json:"dirty"`
+
+	// Erratic makes sucking nondeterministic: on a dirty
+	// square it can also clean the adjacent squares, and
+	// on a clean square it can deposit dirt
+	Erratic bool `json:"erratic"`
+}
+
+// Name returns the name of the environment
+func (v *VacuumEnvironment) Name() string {
+	return v.EnvironmentName
+}
+
+// Start returns the node of the starting position and dirt
+func (v *VacuumEnvironment) Start() Node {
+	dirty := make([]bool, len(v.Dirty))
+	copy(dirty, v.Dirty)
+
+	return &VacuumNode{
+		env:      v,
+		position: v.Position,
+		dirty:    dirty,
+		action:   "start",
+	}
+}
+
+// IsGoalNode checks if every square is clean
+func (v *VacuumEnvironment) IsGoalNode(n Node) bool {
+	vacuumNode, ok := n.(*VacuumNode)
+	if !ok {
+		return false
+	}
+
+	for _, dirty := range vacuumNode.dirty {
+		if dirty {
+			return false
+		}
+	}
+	return true
+}
+
+// VisualizeSolution prints out every world state
+// on the way to the node
+func (v *VacuumEnvironment) VisualizeSolution(n Node) {
+	vacuumNode, ok := n.(*VacuumNode)
+	if !ok {
+		return
+	}
+
+	states := make([]string, 0, 16)
+	for parent := vacuumNode; parent != nil; parent = parent.parent {
+		states = append(states, parent.Name())
+	}
+	reverse(states)
+
+	fmt.Println(strings.Join(states, " -> "))
+}
+
+// Validate checks that there are squares
+// and that the vacuum is on one of them
+func (v *VacuumEnvironment) Validate() error {
+	if len(v.Dirty) == 0 {
+		return fmt.Errorf("must supply dirty squares when using type vacuum")
+	}
+
+	if v.Position < 0 || v.Position >= len(v.Dirty) {
+		return fmt.Errorf("position %d is outside of the %d squares", v.Position, len(v.Dirty))
+	}
+
+	return nil
+}
+
+// VacuumNode is the position of the vacuum
+// and the dirt in every square
+type VacuumNode struct {
+	env      *VacuumEnvironment
+	parent   *VacuumNode
+	position int
+	dirty    []bool
+
+	// action is the action (or, for outcomes,
+	// the result of the action) taken to get here
+	action string
+
+	// outcomes are the possible results of
+	// an erratic suck, if this node is one
+	outcomes []*VacuumNode
+
+	// outcome is true if this node is one of
+	// the possible results of an erratic suck
+	outcome bool
+}
+
+func (n *VacuumNode) load(position int, dirty []bool, action string) *VacuumNode {
+	return &VacuumNode{
+		env:      n.env,
+		parent:   n,
+		position: position,
+		dirty:    dirty,
+		action:   action,
+	}
+}
+
+// Name shows every square, with dirty squares as #,
+// clean squares as ., and the vacuum's square in brackets
+func (n *VacuumNode) Name() string {
+	var b strings.Builder
+	for i, dirty := range n.dirty {
+		square := "."
+		if dirty {
+			square = "#"
+		}
+
+		if i == n.position {
+			square = "[" + square + "]"
+		}
+		b.WriteString(square)
+	}
+	return b.String()
+}
+
+// Key returns the position and which squares are
+// dirty, or the name if there are too many squares
+func (n *VacuumNode) Key() NodeKey {
+	if len(n.dirty) > 64 {
+		return NameKey(n.Name())
+	}
+
+	var dirty uint64
+	for i, isDirty := range n.dirty {
+		if isDirty {
+			dirty |= 1 << i
+		}
+	}
+	return Key(uint64(n.position), dirty)
+}
+
+// Parent returns the parent node, and nil if the start node
+func (n *VacuumNode) Parent() Node {
+	if n.parent == nil {
+		return nil // this is required in order to allow nil comparisons
+	}
+	return n.parent
+}
+
+// Children returns left/right, if possible, and suck
+func (n *VacuumNode) Children() []Node {
+	out := make([]Node, 0, 3)
+
+	if n.position > 0 {
+		out = append(out, n.load(n.position-1, n.dirty, "left"))
+	}
+	if n.position < len(n.dirty)-1 {
+		out = append(out, n.load(n.position+1, n.dirty, "right"))
+	}
+
+	return append(out, n.suck())
+}
+
+// suck returns the node of sucking at the current position,
+// which is a chance node if the environment is erratic
+func (n *VacuumNode) suck() *VacuumNode {
+	cleaned := n.withDirt(false, n.position)
+	suck := n.load(n.position, cleaned, "suck")
+	if !n.env.Erratic {
+		return suck
+	}
+
+	if n.dirty[n.position] {
+		suck.addOutcome(cleaned, "cleaned")
+		suck.addOutcome(n.withDirt(false, n.position-1, n.position, n.position+1), "cleaned adjacent")
+	} else {
+		suck.addOutcome(cleaned, "nothing")
+		suck.addOutcome(n.withDirt(true, n.position), "deposited dirt")
+	}
+	return suck
+}
+
+// addOutcome adds a possible result of the chance
+// node, if it's different from the existing ones
+func (n *VacuumNode) addOutcome(dirty []bool, action string) {
+	outcome := n.load(n.position, dirty, action)
+	outcome.outcome = true
+	for _, existing := range n.outcomes {
+		if existing.IsNode(outcome) {
+			return
+		}
+	}
+	n.outcomes = append(n.outcomes, outcome)
+}
+
+// withDirt returns a copy of the dirt with the
+// squares (if they exist) set to dirty or clean
+func (n *VacuumNode) withDirt(dirty bool, squares ...int) []bool {
+	out := make([]bool, len(n.dirty))
+	copy(out, n.dirty)
+	for _, square := range squares {
+		if square >= 0 && square < len(out) {
+			out[square] = dirty
+		}
+	}
+	return out
+}
+
+// Outcomes returns the equally likely results of
+// an erratic suck, and none for other actions
+func (n *VacuumNode) Outcomes() []Outcome {
+	out := make([]Outcome, len(n.outcomes))
+	for i, outcome := range n.outcomes {
+		out[i] = Outcome{
+			Node:        outcome,
+			Probability: 1 / float64(len(n.outcomes)),
+		}
+	}
+	return out
+}
+
+// Cost is 1 for every action, and 0 for
+// the start and the outcomes of an action
+func (n *VacuumNode) Cost() int {
+	if n.parent == nil || n.outcome {
+		return 0
+	}
+	return 1
+}
+
+// Heuristic is the number of dirty squares,
+// as each needs to be sucked at least once
+func (n *VacuumNode) Heuristic() int {
+	total := 0
+	for _, dirty := range n.dirty {
+		if dirty {
+			total++
+		}
+	}
+	return total
+}
+
+// Steps traverses through the parents to
+// determine the sequence of actions taken
+func (n *VacuumNode) Steps() []string {
+	names := make([]string, 1, 128)
+	names[0] = n.action
+	nextParent := n.parent
+	for nextParent != nil {
+		names = append(names, nextParent.action)
+		nextParent = nextParent.parent
+	}
+	reverse(names)
+	return names
+}
+
+// IsNode checks equality with another node by
+// comparing the position and dirt
+func (n *VacuumNode) IsNode(other Node) bool {
+	if otherVacuumNode, ok := other.(*VacuumNode); ok {
+		if otherVacuumNode == nil || n == nil {
+			return false
+		}
+		return otherVacuumNode.Name() == n.Name()
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package environments
+
+import "fmt"
+
+var _ WaypointEnvironment = &GridEnvironment{}
+var _ WaypointEnvironment = &StateEnvironment{}
+
+// WaypointEnvironment is an environment where paths can be
+// searched for between any two named waypoints (nodes), so
+// that a route visiting several of them can be planned
+type WaypointEnvironment interface {
+	Environment
+
+	// Endpoints returns the names of the
+	// start node and the goal node
+	Endpoints() (start, goal string)
+
+	// Between returns a copy of the environment that
+	// starts at one waypoint and has the other as its goal
+	Between(from, to string) (Environment, error)
+
+	// Path returns the node reached by following the named
+	// nodes from the first, which must each be a child of
+	// the one before it
+	Path(names []string) (Node, error)
+}
+
+// Endpoints returns the names of the start and end points
+func (g *GridEnvironment) Endpoints() (string, string) {
+	return g.start.name(), g.end.name()
+}
+
+// Between returns a copy of the grid with
+// the start and end points moved
+func (g *GridEnvironment) Between(from, to string) (Environment, error) {
+	start, err := g.waypoint(from)
+	if err != nil {
+		return nil, err
+	}
+	end, err := g.waypoint(to)
+	if err != nil {
+		return nil, err
+	}
+
+	between := *g
+	between.start = &start
+	between.end = &end
+	return &between, nil
+}
+
+// Path returns the node reached by moving through
+// the named points, which must be neighbors
+func (g *GridEnvironment) Path(names []string) (Node, error) {
+	points := make([]Vector2D, len(names))
+	for i, name := range names {
+		pnt, err := g.waypoint(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 && points[i-1].ManhattanDistanceTo(pnt) > 1 {
+			return nil, fmt.Errorf("point %s of path is not a neighbor of %s", name, names[i-1])
+		}
+		points[i] = pnt
+	}
+	return g.PathThrough(points)
+}
+
+// waypoint parses the name of a passable point
+func (g *GridEnvironment) waypoint(name string) (Vector2D, error) {
+	pnt, err := parsePointName(name)
+	if err != nil {
+		return Vector2D{}, err
+	}
+
+	if !g.passable(pnt) {
+		return Vector2D{}, fmt.Errorf("waypoint %s is impassable", name)
+	}
+	return pnt, nil
+}
+
+// Endpoints returns the names of the start and goal states
+func (l *StateEnvironment) Endpoints() (string, string) {
+	return l.StartNode, l.GoalNode
+}
+
+// Between returns a copy of the environment with the start
+// and goal states moved. The heuristics of the states are
+// only kept if the goal is the same, as they estimate the
+// distance to it
+func (l *StateEnvironment) Between(from, to string) (Environment, error) {
+	if _, ok := l.States[from]; !ok {
+		return nil, fmt.Errorf("waypoint %s missing from states", from)
+	}
+	if _, ok := l.States[to]; !ok {
+		return nil, fmt.Errorf("waypoint %s missing from states", to)
+	}
+
+	between := *l
+	between.StartNode = from
+	between.GoalNode = to
+	if to != l.GoalNode {
+		between.heuristic = func(string) float64 { return 0 }
+	}
+	return &between, nil
+}
+
+// Path returns the node reached by moving
+// through the named states
+func (l *StateEnvironment) Path(names []string) (Node, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("path must contain at least one state")
+	}
+
+	if _, ok := l.States[names[0]]; !ok {
+		return nil, fmt.Errorf("state %s of path missing from states", names[0])
+	}
+
+	node := l.States.loadNode(names[0], 0, nil, l)
+	for i, name := range names[1:] {
+		if _, ok := l.States[names[i]].Children[name]; !ok {
+			return nil, fmt.Errorf("state %s of path is not a child of %s", name, names[i])
+		}
+		node = l.States.loadNode(name, l.States[names[i]].Children[name], node, l)
+	}
+	return node, nil
+}
@@ -0,0 +1,326 @@
+// Package hpa implements the abstraction used by hierarchical
+// pathfinding (HPA*) on grid environments. A grid is
+// partitioned into square clusters, the entrances between
+// neighboring clusters become the nodes of an abstract graph,
+// and the costs of moving between entrances within a cluster
+// are precomputed. Queries search the much smaller abstract
+// graph, then refine the result into a path on the grid.
+//
+// See https://webdocs.cs.ualberta.ca/~mmueller/ps/hpastar.pdf
+package hpa
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+// errTimed is returned for grids with moving obstacles, as
+// the costs between entrances are computed without time
+var errTimed = errors.New("grid has moving obstacles, which the abstraction can't avoid")
+
+const (
+	// maxSingleTransitionWidth is the widest an entrance can
+	// be while only having a single transition in its middle.
+	// Wider entrances have a transition at both ends
+	maxSingleTransitionWidth = 5
+)
+
+// Point is a point on the grid, which unlike
+// environments.Vector2D can be serialised
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+func pointOf(v environments.Vector2D) Point {
+	return Point{X: v.X(), Y: v.Y()}
+}
+
+func (p Point) vector() environments.Vector2D {
+	return environments.NewVector2D(p.X, p.Y)
+}
+
+func (p Point) manhattanDistanceTo(other Point) int {
+	return abs(other.X-p.X) + abs(other.Y-p.Y)
+}
+
+// Edge is a directed edge of the abstract graph
+type Edge struct {
+	// To is the index of the node the edge leads to
+	To int `json:"to"`
+
+	// Cost is the cost of the shortest path
+	// between the nodes on the grid
+	Cost int `json:"cost"`
+}
+
+// Abstraction is the abstract graph of a grid environment
+type Abstraction struct {
+	ClusterSize int `json:"cluster_size"`
+	Width       int `json:"width"`
+	Height      int `json:"height"`
+
+	// Checksum identifies the costs of the grid the
+	// abstraction was built from, so that it isn't
+	// used with a different grid
+	Checksum string `json:"checksum"`
+
+	// Nodes contains the point of every
+	// transition between clusters
+	Nodes []Point `json:"nodes"`
+
+	// Edges contains the edges leaving each node
+	Edges [][]Edge `json:"edges"`
+}
+
+// Build partitions the grid into clusters of the provided
+// size, and builds the abstract graph of their entrances.
+// It returns search.ErrCancelled if the context is cancelled
+func Build(ctx search.Context, env *environments.GridEnvironment, clusterSize int) (*Abstraction, error) {
+	if clusterSize < 2 {
+		return nil, fmt.Errorf("cluster size must be at least 2, but was %d", clusterSize)
+	}
+	if env.Timed() {
+		return nil, errTimed
+	}
+
+	g := grid{env: env}
+	size := env.Size()
+	a := &Abstraction{
+		ClusterSize: clusterSize,
+		Width:       size.X(),
+		Height:      size.Y(),
+		Checksum:    checksum(env),
+	}
+
+	b := &builder{
+		Abstraction: a,
+		ctx:         ctx,
+		grid:        g,
+		indexes:     make(map[Point]int, 256),
+	}
+
+	b.addEntrances()
+	if err := b.addIntraClusterEdges(); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Load reads an abstraction saved with Save
+func Load(r io.Reader) (*Abstraction, error) {
+	a := &Abstraction{}
+	if err := json.NewDecoder(r).Decode(a); err != nil {
+		return nil, fmt.Errorf("could not decode abstraction: %w", err)
+	}
+
+	if len(a.Nodes) != len(a.Edges) {
+		return nil, fmt.Errorf("abstraction has %d nodes but edges for %d", len(a.Nodes), len(a.Edges))
+	}
+
+	for from, edges := range a.Edges {
+		for _, edge := range edges {
+			if edge.To < 0 || edge.To >= len(a.Nodes) {
+				return nil, fmt.Errorf("edge from node %d leads to missing node %d", from, edge.To)
+			}
+		}
+	}
+
+	return a, nil
+}
+
+// Save writes the abstraction so it can be loaded later
+func (a *Abstraction) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(a)
+}
+
+// EdgeCount returns the number of edges in the abstract graph
+func (a *Abstraction) EdgeCount() int {
+	total := 0
+	for _, edges := range a.Edges {
+		total += len(edges)
+	}
+	return total
+}
+
+// Matches returns an error if the abstraction
+// wasn't built from the grid
+func (a *Abstraction) Matches(env *environments.GridEnvironment) error {
+	size := env.Size()
+	if size.X() != a.Width || size.Y() != a.Height {
+		return fmt.Errorf("abstraction is for a %dx%d grid, but the grid is %dx%d", a.Width, a.Height, size.X(), size.Y())
+	}
+
+	if checksum(env) != a.Checksum {
+		return fmt.Errorf("abstraction was built from a grid with different costs")
+	}
+
+	return nil
+}
+
+// cluster returns the bounds of the cluster containing the point
+func (a *Abstraction) cluster(p Point) bounds {
+	min := Point{
+		X: p.X / a.ClusterSize * a.ClusterSize,
+		Y: p.Y / a.ClusterSize * a.ClusterSize,
+	}
+	return bounds{
+		min: min,
+		max: Point{
+			X: minInt(min.X+a.ClusterSize, a.Width),
+			Y: minInt(min.Y+a.ClusterSize, a.Height),
+		},
+	}
+}
+
+// builder contains the state used while
+// building an abstraction
+type builder struct {
+	*Abstraction
+
+	ctx     search.Context
+	grid    grid
+	indexes map[Point]int
+}
+
+// addNode returns the index of the point's
+// node, adding it if it doesn't exist
+func (b *builder) addNode(p Point) int {
+	if idx, ok := b.indexes[p]; ok {
+		return idx
+	}
+
+	b.indexes[p] = len(b.Nodes)
+	b.Nodes = append(b.Nodes, p)
+	b.Edges = append(b.Edges, []Edge{})
+	return b.indexes[p]
+}
+
+// addEntrances finds the entrances along every border
+// between clusters, adding a node on both sides of
+// each of their transitions
+func (b *builder) addEntrances() {
+	// borders between clusters side by side
+	for x := b.ClusterSize; x < b.Width; x += b.ClusterSize {
+		for y := 0; y < b.Height; y += b.ClusterSize {
+			b.addBorder(Point{X: x - 1, Y: y}, Point{X: x, Y: y}, Point{Y: 1}, minInt(b.ClusterSize, b.Height-y))
+		}
+	}
+
+	// borders between clusters above one another
+	for y := b.ClusterSize; y < b.Height; y += b.ClusterSize {
+		for x := 0; x < b.Width; x += b.ClusterSize {
+			b.addBorder(Point{X: x, Y: y - 1}, Point{X: x, Y: y}, Point{X: 1}, minInt(b.ClusterSize, b.Width-x))
+		}
+	}
+}
+
+// addBorder adds the transitions of every entrance along
+// a border, which is a run of points where both sides are
+// passable. The sides start at the points and move along
+// the step for the length of the border
+func (b *builder) addBorder(side, otherSide, step Point, length int) {
+	runStart := -1
+	for i := 0; i <= length; i++ {
+		open := i < length && b.grid.passable(offset(side, step, i)) && b.grid.passable(offset(otherSide, step, i))
+		if open && runStart == -1 {
+			runStart = i
+		} else if !open && runStart != -1 {
+			for _, transition := range transitions(runStart, i) {
+				b.addTransition(offset(side, step, transition), offset(otherSide, step, transition))
+			}
+			runStart = -1
+		}
+	}
+}
+
+// addTransition adds the nodes on either side of a
+// transition, and the edges between them
+func (b *builder) addTransition(p, other Point) {
+	from, to := b.addNode(p), b.addNode(other)
+
+	toCost, _ := b.grid.cost(other)
+	fromCost, _ := b.grid.cost(p)
+	b.Edges[from] = append(b.Edges[from], Edge{To: to, Cost: toCost})
+	b.Edges[to] = append(b.Edges[to], Edge{To: from, Cost: fromCost})
+}
+
+// addIntraClusterEdges adds edges between every
+// pair of nodes in the same cluster that can reach
+// each other without leaving the cluster, which is
+// where building spends most of its time
+func (b *builder) addIntraClusterEdges() error {
+	clusters := make(map[bounds][]int, 64)
+	for idx, p := range b.Nodes {
+		cluster := b.cluster(p)
+		clusters[cluster] = append(clusters[cluster], idx)
+	}
+
+	for cluster, nodes := range clusters {
+		if b.ctx.Cancelled() {
+			return search.ErrCancelled
+		}
+
+		for _, from := range nodes {
+			result := b.grid.search(b.Nodes[from], cluster, false, nil)
+			for _, to := range nodes {
+				if cost, ok := result.distance[b.Nodes[to]]; ok && to != from {
+					b.Edges[from] = append(b.Edges[from], Edge{To: to, Cost: cost})
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// transitions returns where the transitions
+// of an entrance from start to end should be
+func transitions(start, end int) []int {
+	if end-start <= maxSingleTransitionWidth {
+		return []int{(start + end - 1) / 2}
+	}
+	return []int{start, end - 1}
+}
+
+func offset(p, step Point, n int) Point {
+	return Point{X: p.X + step.X*n, Y: p.Y + step.Y*n}
+}
+
+// checksum hashes the cost of every point of the grid
+func checksum(env *environments.GridEnvironment) string {
+	size := env.Size()
+	hash := sha256.New()
+	for y := 0; y < size.Y(); y++ {
+		for x := 0; x < size.X(); x++ {
+			cost, passable := env.PointCost(environments.NewVector2D(x, y))
+			if !passable {
+				cost = -1
+			}
+			fmt.Fprintf(hash, "%d,", cost)
+		}
+		fmt.Fprintln(hash)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
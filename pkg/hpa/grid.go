@@ -0,0 +1,136 @@
+package hpa
+
+import (
+	"container/heap"
+
+	"github.com/porgull/go-search/pkg/environments"
+)
+
+// bounds is a rectangle of points, from min
+// (inclusive) to max (exclusive)
+type bounds struct {
+	min Point
+	max Point
+}
+
+func (b bounds) contains(p Point) bool {
+	return p.X >= b.min.X && p.Y >= b.min.Y && p.X < b.max.X && p.Y < b.max.Y
+}
+
+// grid provides access to the points
+// of a grid environment
+type grid struct {
+	env *environments.GridEnvironment
+}
+
+func (g grid) cost(p Point) (int, bool) {
+	return g.env.PointCost(p.vector())
+}
+
+func (g grid) passable(p Point) bool {
+	_, passable := g.cost(p)
+	return passable
+}
+
+var neighborSteps = []Point{{Y: -1}, {Y: 1}, {X: -1}, {X: 1}}
+
+// searchResult contains the shortest paths
+// found by a search
+type searchResult struct {
+	distance map[Point]int
+
+	// previous contains the point before each
+	// point on its shortest path from the source
+	previous map[Point]Point
+
+	expanded int
+}
+
+// search runs Dijkstra's algorithm from the source, only
+// moving between points within the bounds, and stopping
+// early if the target (if provided) is reached. If reverse
+// is true, it finds the distances from every point to the
+// source, rather than from the source to every point
+func (g grid) search(source Point, within bounds, reverse bool, target *Point) searchResult {
+	result := searchResult{
+		distance: map[Point]int{source: 0},
+		previous: make(map[Point]Point, 64),
+	}
+
+	queue := &pointQueue{{point: source}}
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(queuedPoint)
+		if current.distance > result.distance[current.point] {
+			continue // already found a shorter route
+		}
+		result.expanded++
+
+		if target != nil && current.point == *target {
+			break
+		}
+
+		currentCost, _ := g.cost(current.point)
+		for _, step := range neighborSteps {
+			next := Point{X: current.point.X + step.X, Y: current.point.Y + step.Y}
+			nextCost, passable := g.cost(next)
+			if !passable || !within.contains(next) {
+				continue
+			}
+
+			// the cost of a move is the cost of the point moved
+			// into, which is the current point when reversed
+			distance := current.distance + nextCost
+			if reverse {
+				distance = current.distance + currentCost
+			}
+
+			if previous, seen := result.distance[next]; seen && previous <= distance {
+				continue
+			}
+			result.distance[next] = distance
+			result.previous[next] = current.point
+			heap.Push(queue, queuedPoint{point: next, distance: distance})
+		}
+	}
+
+	return result
+}
+
+// path returns the points from the source to the
+// point, which must have been reached by the search
+func (r searchResult) path(to Point) []Point {
+	out := []Point{to}
+	for {
+		previous, ok := r.previous[out[len(out)-1]]
+		if !ok {
+			break
+		}
+		out = append(out, previous)
+	}
+
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+type queuedPoint struct {
+	point    Point
+	distance int
+}
+
+// pointQueue implements heap.Interface, popping
+// the point with the lowest distance first
+type pointQueue []queuedPoint
+
+func (q pointQueue) Len() int            { return len(q) }
+func (q pointQueue) Less(i, j int) bool  { return q[i].distance < q[j].distance }
+func (q pointQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *pointQueue) Push(x interface{}) { *q = append(*q, x.(queuedPoint)) }
+func (q *pointQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	popped := old[n-1]
+	*q = old[:n-1]
+	return popped
+}
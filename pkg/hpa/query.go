@@ -0,0 +1,229 @@
+package hpa
+
+import (
+	"container/heap"
+	"fmt"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+// Path is the result of a query
+type Path struct {
+	// Points contains every point of the path on the
+	// grid, from the start point to the goal point
+	Points []environments.Vector2D
+
+	Cost int
+
+	// AbstractLength is the number of abstract
+	// nodes the path was refined from
+	AbstractLength int
+
+	// Expanded is the number of nodes expanded
+	// in both the abstract graph and the grid
+	Expanded int
+}
+
+// FindPath finds a path between the points by connecting
+// them to the abstract graph, searching the abstract graph,
+// and refining the abstract path into a path on the grid.
+// The path is usually close to, but not always, the shortest.
+// It returns search.ErrCancelled if the context is cancelled
+func (a *Abstraction) FindPath(ctx search.Context, env *environments.GridEnvironment, from, to environments.Vector2D) (Path, error) {
+	if err := a.Matches(env); err != nil {
+		return Path{}, err
+	}
+	if env.Timed() {
+		return Path{}, errTimed
+	}
+
+	q := &query{
+		Abstraction: a,
+		ctx:         ctx,
+		grid:        grid{env: env},
+		start:       len(a.Nodes),
+		goal:        len(a.Nodes) + 1,
+		extraEdges:  make(map[int][]Edge, 32),
+	}
+	q.points = append(append(make([]Point, 0, len(a.Nodes)+2), a.Nodes...), pointOf(from), pointOf(to))
+
+	q.connectStartAndGoal()
+
+	abstractPath, err := q.searchAbstract()
+	if err != nil {
+		return Path{}, err
+	}
+
+	path, err := q.refine(abstractPath)
+	if err != nil {
+		return Path{}, err
+	}
+	path.Expanded = q.expanded
+	return path, nil
+}
+
+// query contains the state used while answering a query
+type query struct {
+	*Abstraction
+	ctx  search.Context
+	grid grid
+
+	// start and goal are the indexes of
+	// the temporary start and goal nodes
+	start int
+	goal  int
+
+	// points contains the points of the
+	// nodes, including the start and goal
+	points []Point
+
+	// extraEdges contains the temporary edges
+	// connecting the start and goal to the graph
+	extraEdges map[int][]Edge
+
+	expanded int
+}
+
+// connectStartAndGoal adds edges from the start to the nodes in
+// its cluster, and to the goal from the nodes in its cluster
+func (q *query) connectStartAndGoal() {
+	startPoint, goalPoint := q.points[q.start], q.points[q.goal]
+	startCluster, goalCluster := q.cluster(startPoint), q.cluster(goalPoint)
+
+	fromStart := q.grid.search(startPoint, startCluster, false, nil)
+	toGoal := q.grid.search(goalPoint, goalCluster, true, nil)
+	q.expanded += fromStart.expanded + toGoal.expanded
+
+	for idx, p := range q.Nodes {
+		if cost, ok := fromStart.distance[p]; ok && startCluster.contains(p) {
+			q.extraEdges[q.start] = append(q.extraEdges[q.start], Edge{To: idx, Cost: cost})
+		}
+		if cost, ok := toGoal.distance[p]; ok && goalCluster.contains(p) {
+			q.extraEdges[idx] = append(q.extraEdges[idx], Edge{To: q.goal, Cost: cost})
+		}
+	}
+
+	// the start and goal might be connected directly
+	if cost, ok := fromStart.distance[goalPoint]; ok {
+		q.extraEdges[q.start] = append(q.extraEdges[q.start], Edge{To: q.goal, Cost: cost})
+	}
+}
+
+func (q *query) edges(idx int) []Edge {
+	if idx >= len(q.Edges) {
+		return q.extraEdges[idx]
+	}
+
+	extra, ok := q.extraEdges[idx]
+	if !ok {
+		return q.Edges[idx]
+	}
+
+	// copy so that the abstraction's edges aren't changed
+	out := make([]Edge, 0, len(q.Edges[idx])+len(extra))
+	return append(append(out, q.Edges[idx]...), extra...)
+}
+
+// searchAbstract runs A* on the abstract graph from the
+// start to the goal, returning the indexes of the path
+func (q *query) searchAbstract() ([]int, error) {
+	goalPoint := q.points[q.goal]
+	distance := map[int]int{q.start: 0}
+	previous := make(map[int]int, 64)
+
+	queue := &nodeQueue{{node: q.start, priority: q.points[q.start].manhattanDistanceTo(goalPoint)}}
+	for queue.Len() > 0 {
+		if q.ctx.Cancelled() {
+			return nil, search.ErrCancelled
+		}
+
+		current := heap.Pop(queue).(queuedNode)
+		idx := current.node
+		if current.priority > distance[idx]+q.points[idx].manhattanDistanceTo(goalPoint) {
+			continue // already found a shorter route
+		}
+		q.expanded++
+
+		if idx == q.goal {
+			path := []int{idx}
+			for idx != q.start {
+				idx = previous[idx]
+				path = append([]int{idx}, path...)
+			}
+			return path, nil
+		}
+
+		for _, edge := range q.edges(idx) {
+			nextDistance := distance[idx] + edge.Cost
+			if previous, seen := distance[edge.To]; seen && previous <= nextDistance {
+				continue
+			}
+			distance[edge.To] = nextDistance
+			previous[edge.To] = idx
+			heap.Push(queue, queuedNode{
+				node:     edge.To,
+				priority: nextDistance + q.points[edge.To].manhattanDistanceTo(goalPoint),
+			})
+		}
+	}
+
+	return nil, fmt.Errorf("abstract graph is exhausted; could not find a path to the goal")
+}
+
+// refine turns the abstract path into a path on the grid
+// by finding the path along each edge within its cluster
+func (q *query) refine(abstractPath []int) (Path, error) {
+	points := []Point{q.points[abstractPath[0]]}
+	for i := 1; i < len(abstractPath); i++ {
+		if q.ctx.Cancelled() {
+			return Path{}, search.ErrCancelled
+		}
+
+		from, to := q.points[abstractPath[i-1]], q.points[abstractPath[i]]
+		if from.manhattanDistanceTo(to) <= 1 {
+			if from != to {
+				points = append(points, to)
+			}
+			continue
+		}
+
+		result := q.grid.search(from, q.cluster(from), false, &to)
+		q.expanded += result.expanded
+		points = append(points, result.path(to)[1:]...)
+	}
+
+	path := Path{
+		Points:         make([]environments.Vector2D, len(points)),
+		AbstractLength: len(abstractPath),
+	}
+	for i, p := range points {
+		path.Points[i] = p.vector()
+		if i > 0 {
+			cost, _ := q.grid.cost(p)
+			path.Cost += cost
+		}
+	}
+	return path, nil
+}
+
+type queuedNode struct {
+	node     int
+	priority int
+}
+
+// nodeQueue implements heap.Interface, popping
+// the node with the lowest priority first
+type nodeQueue []queuedNode
+
+func (q nodeQueue) Len() int            { return len(q) }
+func (q nodeQueue) Less(i, j int) bool  { return q[i].priority < q[j].priority }
+func (q nodeQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *nodeQueue) Push(x interface{}) { *q = append(*q, x.(queuedNode)) }
+func (q *nodeQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	popped := old[n-1]
+	*q = old[:n-1]
+	return popped
+}
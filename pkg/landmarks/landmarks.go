@@ -0,0 +1,216 @@
+// Package landmarks implements the ALT (A*, landmarks and
+// the triangle inequality) heuristic for state environments.
+// The distances to and from a few landmark states are
+// precomputed, which bound the distance between any two
+// states without needing coordinates or a hand-made heuristic.
+//
+// See https://www.microsoft.com/en-us/research/publication/computing-the-shortest-path-a-search-meets-graph-theory/
+package landmarks
+
+import (
+	"container/heap"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/porgull/go-search/pkg/environments"
+)
+
+// Landmarks contains the distances between
+// the landmark states and every other state
+type Landmarks struct {
+	// Names contains the names of the landmark states
+	Names []string
+
+	// from contains the distance from each
+	// landmark to every state it can reach
+	from []map[string]float64
+
+	// to contains the distance to each landmark
+	// from every state that can reach it
+	to []map[string]float64
+}
+
+// Compute computes the distances to and from the landmarks
+func Compute(states environments.States, names []string) (*Landmarks, error) {
+	l := &Landmarks{
+		Names: names,
+		from:  make([]map[string]float64, len(names)),
+		to:    make([]map[string]float64, len(names)),
+	}
+
+	reversed := reverse(states)
+	for i, name := range names {
+		if _, ok := states[name]; !ok {
+			return nil, fmt.Errorf("landmark %s missing from states", name)
+		}
+
+		l.from[i] = distances(states, name)
+		l.to[i] = distances(reversed, name)
+	}
+
+	return l, nil
+}
+
+// Estimate returns a lower bound of the distance between
+// the states using the triangle inequality: for every
+// landmark L, d(from, to) >= d(L, to) - d(L, from) and
+// d(from, to) >= d(from, L) - d(to, L)
+func (l *Landmarks) Estimate(from, to string) float64 {
+	best := 0.0
+	for i := range l.Names {
+		fromLandmarkToTarget, ok1 := l.from[i][to]
+		fromLandmarkToSource, ok2 := l.from[i][from]
+		if ok1 && ok2 && fromLandmarkToTarget-fromLandmarkToSource > best {
+			best = fromLandmarkToTarget - fromLandmarkToSource
+		}
+
+		fromSourceToLandmark, ok1 := l.to[i][from]
+		fromTargetToLandmark, ok2 := l.to[i][to]
+		if ok1 && ok2 && fromSourceToLandmark-fromTargetToLandmark > best {
+			best = fromSourceToLandmark - fromTargetToLandmark
+		}
+	}
+	return best
+}
+
+// HeuristicTo returns a heuristic estimating
+// the distance from a state to the goal
+func (l *Landmarks) HeuristicTo(goal string) func(state string) float64 {
+	return func(state string) float64 {
+		return l.Estimate(state, goal)
+	}
+}
+
+// Farthest picks the landmarks one at a time, each being
+// the state farthest from the ones already picked, starting
+// with the state farthest from the start state
+func Farthest(states environments.States, start string, count int) []string {
+	if count > len(states) {
+		count = len(states)
+	}
+
+	// closest contains the distance from each state to
+	// its closest landmark, treating edges as undirected
+	undirected := undirect(states)
+	closest := distances(undirected, start)
+
+	out := make([]string, 0, count)
+	for len(out) < count {
+		next, nextDistance := "", -1.0
+		for _, name := range sortedNames(states) {
+			if distance, ok := closest[name]; ok && distance > nextDistance {
+				next, nextDistance = name, distance
+			}
+		}
+
+		// every reachable state is already a landmark
+		if nextDistance <= 0 && len(out) > 0 {
+			break
+		}
+
+		out = append(out, next)
+		for name, distance := range distances(undirected, next) {
+			if distance < closest[name] {
+				closest[name] = distance
+			}
+		}
+	}
+	return out
+}
+
+// Random picks distinct landmarks at random
+func Random(states environments.States, count int, seed int64) []string {
+	names := sortedNames(states)
+	rand.New(rand.NewSource(seed)).Shuffle(len(names), func(i, j int) {
+		names[i], names[j] = names[j], names[i]
+	})
+
+	if count > len(names) {
+		count = len(names)
+	}
+	return names[:count]
+}
+
+// distances runs Dijkstra's algorithm from the source,
+// returning the distance to every reachable state
+func distances(states environments.States, source string) map[string]float64 {
+	out := map[string]float64{source: 0}
+
+	queue := &stateQueue{{name: source}}
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(queuedState)
+		if current.distance > out[current.name] {
+			continue // already found a shorter route
+		}
+
+		for child, cost := range states[current.name].Children {
+			distance := current.distance + cost
+			if previous, seen := out[child]; seen && previous <= distance {
+				continue
+			}
+			out[child] = distance
+			heap.Push(queue, queuedState{name: child, distance: distance})
+		}
+	}
+	return out
+}
+
+// reverse returns the states with every edge reversed
+func reverse(states environments.States) environments.States {
+	out := make(environments.States, len(states))
+	for name := range states {
+		out[name] = environments.State{Children: map[string]float64{}}
+	}
+
+	for name, state := range states {
+		for child, cost := range state.Children {
+			out[child].Children[name] = cost
+		}
+	}
+	return out
+}
+
+// undirect returns the states with every edge in both
+// directions, using the cheapest cost if both existed
+func undirect(states environments.States) environments.States {
+	out := reverse(states)
+	for name, state := range states {
+		for child, cost := range state.Children {
+			if previous, ok := out[name].Children[child]; !ok || cost < previous {
+				out[name].Children[child] = cost
+			}
+		}
+	}
+	return out
+}
+
+func sortedNames(states environments.States) []string {
+	names := make([]string, 0, len(states))
+	for name := range states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type queuedState struct {
+	name     string
+	distance float64
+}
+
+// stateQueue implements heap.Interface, popping
+// the state with the lowest distance first
+type stateQueue []queuedState
+
+func (q stateQueue) Len() int            { return len(q) }
+func (q stateQueue) Less(i, j int) bool  { return q[i].distance < q[j].distance }
+func (q stateQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *stateQueue) Push(x interface{}) { *q = append(*q, x.(queuedState)) }
+func (q *stateQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	popped := old[n-1]
+	*q = old[:n-1]
+	return popped
+}
@@ -0,0 +1,93 @@
+package mdp
+
+import (
+	"math"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+// PolicyIteration implements policy iteration, which
+// alternates between evaluating the current policy and
+// greedily improving it until the policy stops changing.
+// It can take the `tolerance` and `max_iterations` custom
+// arguments, which bound both the evaluation of each
+// policy and the number of improvements
+type PolicyIteration struct {
+	solverParams
+}
+
+// Solve runs policy iteration on the MDP and returns the solution
+func (p PolicyIteration) Solve(ctx search.Context, m environments.MDP) (Solution, error) {
+	if err := p.setParams(ctx.CustomSearchParams); err != nil {
+		return Solution{}, err
+	}
+
+	states := m.States()
+	values := make(map[string]float64, len(states))
+	policy := make(map[string]string, len(states))
+
+	// start with the first action of every state
+	for _, state := range states {
+		if actions := m.Actions(state); len(actions) > 0 {
+			policy[state] = actions[0]
+		}
+	}
+
+	solution := Solution{
+		MDP:    m,
+		Values: values,
+		Policy: policy,
+	}
+
+	for solution.Iterations < p.maxIterations {
+		solution.Iterations++
+
+		p.evaluate(m, states, policy, values)
+
+		stable := true
+		for _, state := range states {
+			current, ok := policy[state]
+			if !ok {
+				continue
+			}
+
+			action, value := greedyAction(m, values, state)
+			// only switch actions for a strict improvement,
+			// otherwise ties can make the policy flip forever
+			if action != current && value > actionValue(m, values, state, current)+p.tolerance {
+				policy[state] = action
+				stable = false
+			}
+		}
+
+		if stable {
+			solution.Converged = true
+			break
+		}
+	}
+
+	return solution, nil
+}
+
+// evaluate updates the values to be those of
+// following the policy
+func (p *PolicyIteration) evaluate(m environments.MDP, states []string, policy map[string]string, values map[string]float64) {
+	for i := 0; i < p.maxIterations; i++ {
+		delta := 0.0
+		for _, state := range states {
+			action, ok := policy[state]
+			if !ok {
+				continue
+			}
+
+			value := actionValue(m, values, state, action)
+			delta = math.Max(delta, math.Abs(value-values[state]))
+			values[state] = value
+		}
+
+		if delta < p.tolerance {
+			return
+		}
+	}
+}
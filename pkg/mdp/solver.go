@@ -0,0 +1,140 @@
+package mdp
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+const (
+	defaultTolerance     = 1e-6
+	defaultMaxIterations = 1000
+)
+
+// Solver defines the interface for an MDP solver
+type Solver interface {
+	// Solve finds the optimal policy for the MDP
+	Solve(ctx search.Context, m environments.MDP) (Solution, error)
+}
+
+var (
+	// solvers defines the valid
+	// MDP solvers
+	solvers = map[string]Solver{
+		"value_iteration":  ValueIteration{},
+		"policy_iteration": PolicyIteration{},
+	}
+)
+
+// Solvers returns all of the premade solver names
+func Solvers() []string {
+	names := make([]string, len(solvers))
+
+	i := 0
+	for name := range solvers {
+		names[i] = name
+		i++
+	}
+
+	return names
+}
+
+// GetSolver returns the desired solver
+func GetSolver(name string) (Solver, error) {
+	if solver, ok := solvers[name]; ok {
+		return solver, nil
+	}
+	return nil, fmt.Errorf("could not find solver %s", name)
+}
+
+// Solution contains the policy and values found
+// by a solver for an MDP
+type Solution struct {
+	MDP        environments.MDP
+	Iterations int
+
+	// Converged is false if the solver stopped
+	// because it reached its maximum iterations
+	Converged bool
+
+	// Values contains the expected discounted
+	// reward of each state under the policy
+	Values map[string]float64
+
+	// Policy contains the action to take
+	// in each non-terminal state
+	Policy map[string]string
+}
+
+// Print prints the solution to stdout
+func (s Solution) Print() {
+	if s.Converged {
+		fmt.Printf("Converged in %d iterations.\n", s.Iterations)
+	} else {
+		fmt.Printf("Stopped after %d iterations without converging.\n", s.Iterations)
+	}
+
+	for _, state := range s.MDP.States() {
+		action, ok := s.Policy[state]
+		if !ok {
+			action = "(terminal)"
+		}
+		fmt.Printf("%s: %s (value %s)\n", state, action, strconv.FormatFloat(s.Values[state], 'f', 4, 64))
+	}
+
+	s.MDP.VisualizePolicy(s.Policy)
+}
+
+// solverParams contains the parameters
+// shared by every solver
+type solverParams struct {
+	tolerance     float64
+	maxIterations int
+}
+
+func (p *solverParams) setParams(params search.CustomSearchParams) error {
+	p.tolerance = defaultTolerance
+	if tolerance, ok := params["tolerance"]; ok {
+		parsedTolerance, err := strconv.ParseFloat(tolerance, 64)
+		if err != nil {
+			return fmt.Errorf("Could not parse 'tolerance' as float: %w", err)
+		}
+		p.tolerance = parsedTolerance
+	}
+
+	p.maxIterations = defaultMaxIterations
+	if maxIterations, ok := params["max_iterations"]; ok {
+		parsedMaxIterations, err := strconv.ParseInt(maxIterations, 10, 32)
+		if err != nil {
+			return fmt.Errorf("Could not parse 'max_iterations' as integer: %w", err)
+		}
+		p.maxIterations = int(parsedMaxIterations)
+	}
+
+	return nil
+}
+
+// actionValue returns the expected discounted reward
+// of taking the action in the state
+func actionValue(m environments.MDP, values map[string]float64, state, action string) float64 {
+	value := 0.0
+	for _, transition := range m.Transitions(state, action) {
+		value += transition.Probability * (transition.Reward + m.Discount()*values[transition.State])
+	}
+	return value
+}
+
+// greedyAction returns the action with the highest
+// expected discounted reward in the state
+func greedyAction(m environments.MDP, values map[string]float64, state string) (string, float64) {
+	bestAction, bestValue := "", 0.0
+	for _, action := range m.Actions(state) {
+		value := actionValue(m, values, state, action)
+		if bestAction == "" || value > bestValue {
+			bestAction, bestValue = action, value
+		}
+	}
+	return bestAction, bestValue
+}
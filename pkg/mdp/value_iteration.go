@@ -0,0 +1,61 @@
+package mdp
+
+import (
+	"math"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+// ValueIteration implements value iteration, which
+// repeatedly updates every state's value with its
+// best action until the values stop changing. It
+// can take the `tolerance` and `max_iterations`
+// custom arguments
+type ValueIteration struct {
+	solverParams
+}
+
+// Solve runs value iteration on the MDP and returns the solution
+func (v ValueIteration) Solve(ctx search.Context, m environments.MDP) (Solution, error) {
+	if err := v.setParams(ctx.CustomSearchParams); err != nil {
+		return Solution{}, err
+	}
+
+	states := m.States()
+	values := make(map[string]float64, len(states))
+
+	solution := Solution{
+		MDP:    m,
+		Values: values,
+		Policy: make(map[string]string, len(states)),
+	}
+
+	for solution.Iterations < v.maxIterations {
+		solution.Iterations++
+
+		delta := 0.0
+		for _, state := range states {
+			action, value := greedyAction(m, values, state)
+			if action == "" {
+				continue
+			}
+
+			delta = math.Max(delta, math.Abs(value-values[state]))
+			values[state] = value
+		}
+
+		if delta < v.tolerance {
+			solution.Converged = true
+			break
+		}
+	}
+
+	for _, state := range states {
+		if action, _ := greedyAction(m, values, state); action != "" {
+			solution.Policy[state] = action
+		}
+	}
+
+	return solution, nil
+}
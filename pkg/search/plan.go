@@ -0,0 +1,81 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/porgull/go-search/pkg/environments"
+)
+
+// Plan is a conditional plan for environments where
+// steps can have several outcomes (see
+// environments.ChanceNode): the step to take from
+// a node, and the plan to follow after each outcome
+type Plan struct {
+	// Node is the node the plan starts from
+	Node environments.Node
+
+	// Step is the child of Node to take, which
+	// is nil if Node is already the goal
+	Step environments.Node
+
+	// Outcomes contains the plan for every possible
+	// outcome of the step. If the step is deterministic,
+	// its only outcome is the step itself
+	Outcomes []*Plan
+}
+
+// WorstCaseCost returns the highest cost of
+// following the plan until reaching the goal
+func (p *Plan) WorstCaseCost() float64 {
+	worst := 0.0
+	for _, outcome := range p.Outcomes {
+		cost := outcome.WorstCaseCost()
+
+		// add the costs of getting from this
+		// plan's node to the outcome's node
+		for node := outcome.Node; node != nil && node != p.Node; node = node.Parent() {
+			cost += environments.CostOf(node)
+		}
+
+		if cost > worst {
+			worst = cost
+		}
+	}
+	return worst
+}
+
+// Print prints the plan to stdout, with the
+// plan for each outcome indented below its step
+func (p *Plan) Print() {
+	p.print(0)
+}
+
+func (p *Plan) print(depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	if p.Step == nil {
+		fmt.Printf("%sat %s: done\n", indent, p.Node.Name())
+		return
+	}
+
+	fmt.Printf("%sat %s: %s\n", indent, p.Node.Name(), lastStep(p.Step))
+	if len(p.Outcomes) == 1 && p.Outcomes[0].Node == p.Step {
+		p.Outcomes[0].print(depth)
+		return
+	}
+
+	for _, outcome := range p.Outcomes {
+		fmt.Printf("%s  if %s:\n", indent, lastStep(outcome.Node))
+		outcome.print(depth + 2)
+	}
+}
+
+// lastStep returns the step taken to reach the node
+func lastStep(n environments.Node) string {
+	steps := n.Steps()
+	if len(steps) == 0 {
+		return n.Name()
+	}
+	return steps[len(steps)-1]
+}
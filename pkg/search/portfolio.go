@@ -0,0 +1,148 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/porgull/go-search/pkg/environments"
+)
+
+// Searcher is anything that can search an environment,
+// like the algorithms in the algorithms package
+type Searcher interface {
+	Run(ctx Context, e environments.Environment) (Result, error)
+}
+
+// Portfolio runs several searchers concurrently on the same
+// environment, which has to be safe for concurrent reads,
+// and cancels the rest once it has a result
+type Portfolio struct {
+	// Searchers are the searchers to run, by name
+	Searchers map[string]Searcher
+
+	// Deadline is how long to wait for the cheapest
+	// result, or 0 to use the first result found. If
+	// nothing finished by then, the first result
+	// after it is used
+	Deadline time.Duration
+}
+
+// PortfolioResult is the result of the
+// searcher that won the portfolio
+type PortfolioResult struct {
+	Result
+
+	// Searcher is the name of the searcher
+	Searcher string
+}
+
+// portfolioRun is a searcher's result or error
+type portfolioRun struct {
+	name   string
+	result Result
+	err    error
+}
+
+// Run runs every searcher with the context, and returns the first
+// result found or the cheapest one found within the deadline. The
+// other searchers are cancelled, and Run returns once they stop
+func (p Portfolio) Run(ctx Context, e environments.Environment) (PortfolioResult, error) {
+	if len(p.Searchers) == 0 {
+		return PortfolioResult{}, fmt.Errorf("portfolio has no searchers")
+	}
+
+	done := make(chan struct{})
+	var once sync.Once
+	cancel := func() { once.Do(func() { close(done) }) }
+
+	// the searchers also stop if the parent context
+	// is cancelled, so it's passed on to done
+	go func() {
+		select {
+		case <-ctx.Done:
+			cancel()
+		case <-done:
+		}
+	}()
+
+	runs := make(chan portfolioRun, len(p.Searchers))
+	var wg sync.WaitGroup
+	for name, searcher := range p.Searchers {
+		wg.Add(1)
+		go func(name string, searcher Searcher) {
+			defer wg.Done()
+			result, err := searcher.Run(Context{CustomSearchParams: ctx.CustomSearchParams, Done: done}, e)
+			runs <- portfolioRun{name: name, result: result, err: err}
+		}(name, searcher)
+	}
+
+	best, err := p.collect(ctx, e, runs)
+	cancel()
+	wg.Wait()
+
+	return best, err
+}
+
+// collect receives runs until one of them wins,
+// or every searcher has failed
+func (p Portfolio) collect(ctx Context, e environments.Environment, runs <-chan portfolioRun) (PortfolioResult, error) {
+	var deadline <-chan time.Time
+	if p.Deadline > 0 {
+		timer := time.NewTimer(p.Deadline)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	var best *PortfolioResult
+	errs := map[string]error{}
+	for remaining := len(p.Searchers); remaining > 0; {
+		select {
+		case run := <-runs:
+			remaining--
+			if run.err != nil {
+				errs[run.name] = run.err
+				continue
+			}
+
+			if best == nil || run.result.TotalCost() < best.TotalCost() {
+				best = &PortfolioResult{Result: run.result, Searcher: run.name}
+			}
+			if deadline == nil {
+				return *best, nil
+			}
+
+		case <-deadline:
+			if best != nil {
+				return *best, nil
+			}
+			deadline = nil
+
+		case <-ctx.Done:
+			return PortfolioResult{}, ErrCancelled
+		}
+	}
+
+	if best != nil {
+		return *best, nil
+	}
+	return PortfolioResult{}, portfolioError(errs)
+}
+
+// portfolioError combines the errors of every
+// searcher, sorted by their names
+func portfolioError(errs map[string]error) error {
+	names := make([]string, 0, len(errs))
+	for name := range errs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	messages := make([]string, len(names))
+	for i, name := range names {
+		messages[i] = fmt.Sprintf("%s: %s", name, errs[name].Error())
+	}
+	return fmt.Errorf("every searcher failed (%s)", strings.Join(messages, "; "))
+}
@@ -0,0 +1,266 @@
+package search
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/porgull/go-search/pkg/environments"
+)
+
+// Problem is a type-safe search problem, with states of type
+// S that are compared for identity (rather than by name) and
+// actions of type A. Algorithms search it through NewProblemEnvironment
+type Problem[S comparable, A any] struct {
+	// Name is the name of the problem
+	Name string
+
+	// Start is the state the search starts from
+	Start S
+
+	// Successors returns the actions that can be
+	// taken from the state and where they lead
+	Successors func(state S) []Successor[S, A]
+
+	// IsGoal checks if the state is a goal
+	IsGoal func(state S) bool
+
+	// Heuristic estimates the cost of reaching a
+	// goal from the state, and is 0 if it's nil
+	Heuristic func(state S) float64
+
+	// StateName names the state, which has to be unique as
+	// algorithms tell nodes apart by name, and is fmt's %v
+	// if it's nil
+	StateName func(state S) string
+}
+
+// Successor is a state reached by taking
+// an action, and the cost of taking it
+type Successor[S comparable, A any] struct {
+	Action A
+	State  S
+	Cost   float64
+}
+
+// Solution is a path through a problem
+type Solution[S comparable, A any] struct {
+	// States are the states along the path,
+	// starting with the problem's start
+	States []S
+
+	// Actions are the actions taken between the
+	// states, so there's one less than states
+	Actions []A
+
+	Cost       float64
+	Iterations int
+}
+
+// Validate checks that the problem
+// has everything it needs
+func (p Problem[S, A]) Validate() error {
+	if p.Successors == nil {
+		return fmt.Errorf("problem %s has no successors function", p.Name)
+	}
+	if p.IsGoal == nil {
+		return fmt.Errorf("problem %s has no goal function", p.Name)
+	}
+	return nil
+}
+
+func (p Problem[S, A]) heuristic(state S) float64 {
+	if p.Heuristic == nil {
+		return 0
+	}
+	return p.Heuristic(state)
+}
+
+func (p Problem[S, A]) stateName(state S) string {
+	if p.StateName == nil {
+		return fmt.Sprint(state)
+	}
+	return p.StateName(state)
+}
+
+// ProblemEnvironment adapts a problem to environments.Environment,
+// so any algorithm that doesn't need a specific environment can
+// search it
+type ProblemEnvironment[S comparable, A any] struct {
+	Problem Problem[S, A]
+}
+
+// NewProblemEnvironment returns the environment of the problem
+func NewProblemEnvironment[S comparable, A any](p Problem[S, A]) *ProblemEnvironment[S, A] {
+	return &ProblemEnvironment[S, A]{Problem: p}
+}
+
+// Start returns the node of the start state
+func (e *ProblemEnvironment[S, A]) Start() environments.Node {
+	return &ProblemNode[S, A]{
+		env:   e,
+		state: e.Problem.Start,
+	}
+}
+
+// IsGoalNode checks if the node's state is a goal
+func (e *ProblemEnvironment[S, A]) IsGoalNode(n environments.Node) bool {
+	node, ok := n.(*ProblemNode[S, A])
+	return ok && e.Problem.IsGoal(node.state)
+}
+
+// Name returns the name of the problem
+func (e *ProblemEnvironment[S, A]) Name() string {
+	return e.Problem.Name
+}
+
+// VisualizeSolution prints the states along the solution
+func (e *ProblemEnvironment[S, A]) VisualizeSolution(solution environments.Node) {
+	node, ok := solution.(*ProblemNode[S, A])
+	if !ok {
+		return
+	}
+
+	states, _ := node.Path()
+	names := make([]string, len(states))
+	for i, state := range states {
+		names[i] = e.Problem.stateName(state)
+	}
+	fmt.Println("States:", strings.Join(names, " -> "))
+}
+
+// Validate checks that the problem
+// has everything it needs
+func (e *ProblemEnvironment[S, A]) Validate() error {
+	return e.Problem.Validate()
+}
+
+// ProblemNode is a state of a problem, reached
+// by taking an action from its parent
+type ProblemNode[S comparable, A any] struct {
+	env    *ProblemEnvironment[S, A]
+	parent *ProblemNode[S, A]
+
+	state  S
+	action A
+	cost   float64
+}
+
+// State returns the node's state
+func (n *ProblemNode[S, A]) State() S {
+	return n.state
+}
+
+// Action returns the action taken to reach the node,
+// which is A's zero value for the start node
+func (n *ProblemNode[S, A]) Action() A {
+	return n.action
+}
+
+// Path returns the states from the start to the node,
+// and the actions taken between them
+func (n *ProblemNode[S, A]) Path() ([]S, []A) {
+	depth := 0
+	for parent := n; parent != nil; parent = parent.parent {
+		depth++
+	}
+
+	states := make([]S, depth)
+	actions := make([]A, depth-1)
+	for parent, i := n, depth-1; parent != nil; parent, i = parent.parent, i-1 {
+		states[i] = parent.state
+		if i > 0 {
+			actions[i-1] = parent.action
+		}
+	}
+	return states, actions
+}
+
+// Name returns the name of the node's state
+func (n *ProblemNode[S, A]) Name() string {
+	return n.env.Problem.stateName(n.state)
+}
+
+// Parent returns the node this one was reached from
+func (n *ProblemNode[S, A]) Parent() environments.Node {
+	if n.parent == nil {
+		return nil
+	}
+	return n.parent
+}
+
+// Children returns the successors of the node's state
+func (n *ProblemNode[S, A]) Children() []environments.Node {
+	successors := n.env.Problem.Successors(n.state)
+
+	out := make([]environments.Node, len(successors))
+	for i, successor := range successors {
+		out[i] = &ProblemNode[S, A]{
+			env:    n.env,
+			parent: n,
+			state:  successor.State,
+			action: successor.Action,
+			cost:   successor.Cost,
+		}
+	}
+	return out
+}
+
+// Cost returns the cost of the action taken to reach
+// the node, rounded if it isn't a whole number
+func (n *ProblemNode[S, A]) Cost() int {
+	return int(math.Round(n.cost))
+}
+
+// FractionalCost returns the exact cost of
+// the action taken to reach the node
+func (n *ProblemNode[S, A]) FractionalCost() float64 {
+	return n.cost
+}
+
+// Heuristic returns the problem's heuristic of the
+// node's state, rounded if it isn't a whole number
+func (n *ProblemNode[S, A]) Heuristic() int {
+	return int(math.Round(n.env.Problem.heuristic(n.state)))
+}
+
+// FractionalHeuristic returns the problem's
+// exact heuristic of the node's state
+func (n *ProblemNode[S, A]) FractionalHeuristic() float64 {
+	return n.env.Problem.heuristic(n.state)
+}
+
+// Steps returns the actions taken to reach the node
+func (n *ProblemNode[S, A]) Steps() []string {
+	_, actions := n.Path()
+
+	steps := make([]string, len(actions)+1)
+	steps[0] = "start"
+	for i, action := range actions {
+		steps[i+1] = fmt.Sprint(action)
+	}
+	return steps
+}
+
+// IsNode checks if the other node has the same state
+func (n *ProblemNode[S, A]) IsNode(other environments.Node) bool {
+	otherNode, ok := other.(*ProblemNode[S, A])
+	return ok && otherNode != nil && n != nil && otherNode.state == n.state
+}
+
+// SolutionOf returns the solution of a result
+// found on the problem's environment
+func SolutionOf[S comparable, A any](r Result) (Solution[S, A], error) {
+	node, ok := r.Node.(*ProblemNode[S, A])
+	if !ok {
+		return Solution[S, A]{}, fmt.Errorf("result was not found on a problem environment")
+	}
+
+	states, actions := node.Path()
+	return Solution[S, A]{
+		States:     states,
+		Actions:    actions,
+		Cost:       r.TotalCost(),
+		Iterations: r.Iterations,
+	}, nil
+}
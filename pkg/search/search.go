@@ -1,15 +1,35 @@
 package search
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/porgull/go-search/pkg/environments"
 )
 
+// ErrCancelled is returned by algorithms
+// that stop because Done was closed
+var ErrCancelled = errors.New("search was cancelled")
+
 // Context passes search args to search algorithms
 type Context struct {
 	CustomSearchParams CustomSearchParams
+
+	// Done is closed when the algorithm should stop
+	// early, and is never closed if it's nil
+	Done <-chan struct{}
+}
+
+// Cancelled checks if Done is closed
+func (c Context) Cancelled() bool {
+	select {
+	case <-c.Done:
+		return true
+	default:
+		return false
+	}
 }
 
 // CustomSearchParams contains any custom values
@@ -24,18 +44,49 @@ type Result struct {
 	Environment environments.Environment
 	Iterations  int
 
+	// Plan is set instead of Node by algorithms
+	// that find conditional plans
+	Plan *Plan
+
+	// Alternatives contains further solutions after
+	// Node, from cheapest to most expensive, for
+	// algorithms that find several
+	Alternatives []environments.Node
+
 	CustomResultStats map[string]string
 }
 
 // Print prints the results to stdout
 func (r Result) Print() {
+	if r.Plan != nil {
+		r.printPlan()
+		return
+	}
+
 	fmt.Printf("Found node %s in %d iterations.\n", r.Node.Name(), r.Iterations)
 	steps := r.Node.Steps()
 
 	fmt.Printf("Steps (%d): %s\n", len(steps), strings.Join(steps, ", "))
-	fmt.Println("Total cost of solution:", r.TotalCost())
+	fmt.Println("Total cost of solution:", FormatCost(r.TotalCost()))
 	r.Environment.VisualizeSolution(r.Node)
 
+	for i, alternative := range r.Alternatives {
+		steps := alternative.Steps()
+		fmt.Printf("Alternative %d (cost %s, %d steps): %s\n", i+1, FormatCost(pathCost(alternative)), len(steps), strings.Join(steps, ", "))
+	}
+
+	r.printCustomResultStats()
+}
+
+func (r Result) printPlan() {
+	fmt.Printf("Found plan in %d iterations.\n", r.Iterations)
+	fmt.Println("Worst case cost of plan:", FormatCost(r.Plan.WorstCaseCost()))
+	r.Plan.Print()
+
+	r.printCustomResultStats()
+}
+
+func (r Result) printCustomResultStats() {
 	if len(r.CustomResultStats) > 0 {
 		fmt.Println("Custom result data for this run:")
 		for key, val := range r.CustomResultStats {
@@ -45,12 +96,28 @@ func (r Result) Print() {
 }
 
 // TotalCost returns the total cost of the
-// steps taken
-func (r Result) TotalCost() int {
-	total := 0
-	parent := r.Node
+// steps taken, or the worst case cost of the plan
+func (r Result) TotalCost() float64 {
+	if r.Plan != nil {
+		return r.Plan.WorstCaseCost()
+	}
+
+	return pathCost(r.Node)
+}
+
+// FormatCost formats a cost without trailing zeros,
+// so whole costs are printed as integers
+func FormatCost(cost float64) string {
+	return strconv.FormatFloat(cost, 'f', -1, 64)
+}
+
+// pathCost returns the total cost of the
+// steps taken to reach the node
+func pathCost(n environments.Node) float64 {
+	total := 0.0
+	parent := n
 	for parent != nil {
-		total += parent.Cost()
+		total += environments.CostOf(parent)
 		parent = parent.Parent()
 	}
 	return total
@@ -0,0 +1,70 @@
+package shortestpath
+
+import (
+	"fmt"
+
+	"github.com/porgull/go-search/pkg/environments"
+)
+
+// BellmanFord runs the Bellman-Ford algorithm from the source
+// state, which supports negative costs. If a negative cost cycle
+// can be reached from the source, it returns a
+// *NegativeCycleError containing it. See
+// https://en.wikipedia.org/wiki/Bellman%E2%80%93Ford_algorithm
+func BellmanFord(states environments.States, source string) (*Tree, error) {
+	if _, ok := states[source]; !ok {
+		return nil, fmt.Errorf("source state %s missing from states", source)
+	}
+
+	tree := newTree(source)
+	names := sortedNames(states)
+
+	// after relaxing every edge once per state, every shortest
+	// path has been found, unless there's a negative cycle
+	for i := 0; i < len(names); i++ {
+		relaxed := ""
+		for _, name := range names {
+			distance, reached := tree.Distances[name]
+			if !reached {
+				continue
+			}
+
+			for child, cost := range states[name].Children {
+				if previous, seen := tree.Distances[child]; seen && previous <= distance+cost {
+					continue
+				}
+				tree.Distances[child] = distance + cost
+				tree.Predecessors[child] = name
+				relaxed = child
+			}
+		}
+
+		if relaxed == "" {
+			return tree, nil
+		}
+
+		if i == len(names)-1 {
+			return nil, &NegativeCycleError{Cycle: findCycle(tree, relaxed, len(names))}
+		}
+	}
+	return tree, nil
+}
+
+// findCycle returns the cycle of predecessors that the state
+// was relaxed through. Following the predecessors once per
+// state is guaranteed to end up on the cycle
+func findCycle(tree *Tree, state string, count int) []string {
+	for i := 0; i < count; i++ {
+		state = tree.Predecessors[state]
+	}
+
+	cycle := []string{state}
+	for current := tree.Predecessors[state]; current != state; current = tree.Predecessors[current] {
+		cycle = append(cycle, current)
+	}
+
+	for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+		cycle[i], cycle[j] = cycle[j], cycle[i]
+	}
+	return cycle
+}
@@ -0,0 +1,65 @@
+package shortestpath
+
+import (
+	"container/heap"
+	"fmt"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+// Dijkstra runs Dijkstra's algorithm from the start of the
+// environment until every reachable node is settled, and
+// returns the shortest path tree. It uses node names as states,
+// and fails on negative costs, which Dijkstra's algorithm can't
+// handle (see BellmanFord). See
+// https://en.wikipedia.org/wiki/Dijkstra%27s_algorithm
+func Dijkstra(e environments.Environment) (*Tree, error) {
+	start := e.Start()
+	tree := newTree(start.Name())
+
+	queue := &nodeQueue{{node: start}}
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(queuedNode)
+		if current.distance > tree.Distances[current.node.Name()] {
+			continue // already found a shorter route
+		}
+
+		for _, child := range current.node.Children() {
+			cost := environments.CostOf(child)
+			if cost < 0 {
+				return nil, fmt.Errorf("step from %s to %s has negative cost %s", current.node.Name(), child.Name(), search.FormatCost(cost))
+			}
+
+			distance := current.distance + cost
+			if previous, seen := tree.Distances[child.Name()]; seen && previous <= distance {
+				continue
+			}
+			tree.Distances[child.Name()] = distance
+			tree.Predecessors[child.Name()] = current.node.Name()
+			heap.Push(queue, queuedNode{node: child, distance: distance})
+		}
+	}
+	return tree, nil
+}
+
+type queuedNode struct {
+	node     environments.Node
+	distance float64
+}
+
+// nodeQueue implements heap.Interface, popping
+// the node with the lowest distance first
+type nodeQueue []queuedNode
+
+func (q nodeQueue) Len() int            { return len(q) }
+func (q nodeQueue) Less(i, j int) bool  { return q[i].distance < q[j].distance }
+func (q nodeQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *nodeQueue) Push(x interface{}) { *q = append(*q, x.(queuedNode)) }
+func (q *nodeQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	popped := old[n-1]
+	*q = old[:n-1]
+	return popped
+}
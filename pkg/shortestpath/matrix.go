@@ -0,0 +1,163 @@
+package shortestpath
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+// Matrix contains the distances from
+// a set of sources to a set of targets
+type Matrix struct {
+	Sources []string
+	Targets []string
+
+	// distances contains the distance from each source
+	// to each target, if reachable is set for them
+	distances [][]float64
+	reachable [][]bool
+
+	targetIndexes map[string]int
+}
+
+func newMatrix(sources, targets []string) *Matrix {
+	m := &Matrix{
+		Sources:       sources,
+		Targets:       targets,
+		distances:     make([][]float64, len(sources)),
+		reachable:     make([][]bool, len(sources)),
+		targetIndexes: make(map[string]int, len(targets)),
+	}
+	for i := range sources {
+		m.distances[i] = make([]float64, len(targets))
+		m.reachable[i] = make([]bool, len(targets))
+	}
+	for j, target := range targets {
+		m.targetIndexes[target] = j
+	}
+	return m
+}
+
+// NewMatrix builds the matrix from the trees
+// of every source, with the targets as columns
+func NewMatrix(trees []*Tree, targets []string) *Matrix {
+	sources := make([]string, len(trees))
+	for i, tree := range trees {
+		sources[i] = tree.Source
+	}
+
+	m := newMatrix(sources, targets)
+	for i, tree := range trees {
+		for j, target := range targets {
+			m.distances[i][j], m.reachable[i][j] = tree.Distances[target]
+		}
+	}
+	return m
+}
+
+// Distance returns the distance from the source to the target,
+// and false if the target can't be reached or isn't in the matrix
+func (m *Matrix) Distance(source, target string) (float64, bool) {
+	j, ok := m.targetIndexes[target]
+	if !ok {
+		return 0, false
+	}
+
+	for i, name := range m.Sources {
+		if name == source {
+			return m.distances[i][j], m.reachable[i][j]
+		}
+	}
+	return 0, false
+}
+
+// WriteCSV writes the matrix with a row per source and a
+// column per target, leaving unreachable targets empty
+func (m *Matrix) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write(append([]string{"from"}, m.Targets...))
+
+	row := make([]string, len(m.Targets)+1)
+	for i, source := range m.Sources {
+		row[0] = source
+		for j := range m.Targets {
+			row[j+1] = ""
+			if m.reachable[i][j] {
+				row[j+1] = search.FormatCost(m.distances[i][j])
+			}
+		}
+		writer.Write(row)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// FloydWarshall runs the Floyd-Warshall algorithm, which finds
+// the distance between every pair of states and supports
+// negative costs. If there's a negative cost cycle, it returns a
+// *NegativeCycleError containing it. See
+// https://en.wikipedia.org/wiki/Floyd%E2%80%93Warshall_algorithm
+func FloydWarshall(states environments.States) (*Matrix, error) {
+	names := sortedNames(states)
+	m := newMatrix(names, names)
+
+	// next contains the state after each source
+	// on its shortest path to each target
+	next := make([][]int, len(names))
+	for i, name := range names {
+		next[i] = make([]int, len(names))
+		m.distances[i][i], m.reachable[i][i], next[i][i] = 0, true, i
+
+		for child, cost := range states[name].Children {
+			j := m.targetIndexes[child]
+			if !m.reachable[i][j] || cost < m.distances[i][j] {
+				m.distances[i][j], m.reachable[i][j], next[i][j] = cost, true, j
+			}
+		}
+	}
+
+	for k := range names {
+		for i := range names {
+			if !m.reachable[i][k] {
+				continue
+			}
+
+			for j := range names {
+				if !m.reachable[k][j] {
+					continue
+				}
+
+				if distance := m.distances[i][k] + m.distances[k][j]; !m.reachable[i][j] || distance < m.distances[i][j] {
+					m.distances[i][j], m.reachable[i][j], next[i][j] = distance, true, next[i][k]
+				}
+			}
+		}
+
+		// a state with a negative distance to itself is on a
+		// negative cycle, which is found by following next
+		for i := range names {
+			if m.distances[i][i] < 0 {
+				cycle := []string{names[i]}
+				for current := next[i][i]; current != i && len(cycle) < len(names); current = next[current][i] {
+					cycle = append(cycle, names[current])
+				}
+				return nil, &NegativeCycleError{Cycle: cycle}
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func sortedNames(states environments.States) []string {
+	names := make([]string, 0, len(states))
+	for name := range states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,78 @@
+// Package shortestpath implements single-source and all-pairs
+// shortest path algorithms, which find the distance to every
+// state rather than to a single goal. Dijkstra's algorithm works
+// on any environment, while Bellman-Ford and Floyd-Warshall work
+// on the explicit graphs of state environments and support
+// negative costs.
+package shortestpath
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Tree is the shortest path tree from a source state
+type Tree struct {
+	// Source is the name of the state the tree is from
+	Source string
+
+	// Distances contains the distance from the
+	// source to every state it can reach
+	Distances map[string]float64
+
+	// Predecessors contains the state before each
+	// reachable state on its shortest path, except
+	// for the source
+	Predecessors map[string]string
+}
+
+func newTree(source string) *Tree {
+	return &Tree{
+		Source:       source,
+		Distances:    map[string]float64{source: 0},
+		Predecessors: map[string]string{},
+	}
+}
+
+// PathTo returns the states on the shortest path from
+// the source to the state, or nil if it can't be reached
+func (t *Tree) PathTo(state string) []string {
+	if _, ok := t.Distances[state]; !ok {
+		return nil
+	}
+
+	path := []string{state}
+	for state != t.Source {
+		state = t.Predecessors[state]
+		path = append(path, state)
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// Reachable returns the names of every state the
+// source can reach, sorted by name
+func (t *Tree) Reachable() []string {
+	names := make([]string, 0, len(t.Distances))
+	for name := range t.Distances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NegativeCycleError is returned when there's a cycle of states
+// with a negative total cost, so there's no shortest path to
+// any state that can be reached through it
+type NegativeCycleError struct {
+	// Cycle contains the states on the cycle, in order
+	Cycle []string
+}
+
+func (e *NegativeCycleError) Error() string {
+	return fmt.Sprintf("found negative cost cycle %s", strings.Join(append(e.Cycle, e.Cycle[0]), " -> "))
+}
@@ -0,0 +1,186 @@
+package tour
+
+// an order is the indexes of the stops in the order they are
+// visited, always starting with the start (0) and ending with
+// the goal (the last stop)
+
+// exactOrder finds the cheapest order with the Held-Karp
+// dynamic program, which takes O(n^2 2^n) time for n
+// waypoints. See https://en.wikipedia.org/wiki/Held%E2%80%93Karp_algorithm
+func exactOrder(costs [][]float64) []int {
+	goal := len(costs) - 1
+	waypoints := len(costs) - 2
+	if waypoints == 0 {
+		return []int{0, goal}
+	}
+
+	// best[visited][last] is the cheapest cost of starting at
+	// the start, visiting the set of waypoints, and ending at
+	// the last waypoint (which is in the set), and previous
+	// is the waypoint before the last on that path
+	sets := 1 << waypoints
+	best := make([][]float64, sets)
+	previous := make([][]int, sets)
+	for visited := range best {
+		best[visited] = make([]float64, waypoints)
+		previous[visited] = make([]int, waypoints)
+		for last := range best[visited] {
+			best[visited][last] = -1
+		}
+	}
+
+	for last := 0; last < waypoints; last++ {
+		best[1<<last][last] = costs[0][last+1]
+		previous[1<<last][last] = -1
+	}
+
+	for visited := 1; visited < sets; visited++ {
+		for last := 0; last < waypoints; last++ {
+			if best[visited][last] == -1 {
+				continue
+			}
+
+			for next := 0; next < waypoints; next++ {
+				if visited&(1<<next) != 0 {
+					continue
+				}
+
+				cost := best[visited][last] + costs[last+1][next+1]
+				with := visited | 1<<next
+				if best[with][next] == -1 || cost < best[with][next] {
+					best[with][next] = cost
+					previous[with][next] = last
+				}
+			}
+		}
+	}
+
+	all := sets - 1
+	last, lastCost := -1, 0.0
+	for waypoint := 0; waypoint < waypoints; waypoint++ {
+		cost := best[all][waypoint] + costs[waypoint+1][goal]
+		if last == -1 || cost < lastCost {
+			last, lastCost = waypoint, cost
+		}
+	}
+
+	order := make([]int, waypoints+2)
+	order[waypoints+1] = goal
+	for visited, i := all, waypoints; last != -1; i-- {
+		order[i] = last + 1
+		visited, last = visited&^(1<<last), previous[visited][last]
+	}
+	return order
+}
+
+// heuristicOrder builds an order by always visiting the nearest
+// unvisited waypoint, then improves it with 2-opt and or-opt
+// until neither of them finds a cheaper order
+func heuristicOrder(costs [][]float64) []int {
+	order := nearestNeighborOrder(costs)
+
+	for improved := true; improved; {
+		improved = twoOpt(costs, order)
+		if orOpt(costs, order) {
+			improved = true
+		}
+	}
+	return order
+}
+
+// nearestNeighborOrder visits the nearest
+// unvisited waypoint from the last one
+func nearestNeighborOrder(costs [][]float64) []int {
+	goal := len(costs) - 1
+	visited := make([]bool, len(costs))
+
+	order := make([]int, 1, len(costs))
+	for len(order) < goal {
+		last := order[len(order)-1]
+
+		nearest := -1
+		for next := 1; next < goal; next++ {
+			if !visited[next] && (nearest == -1 || costs[last][next] < costs[last][nearest]) {
+				nearest = next
+			}
+		}
+
+		visited[nearest] = true
+		order = append(order, nearest)
+	}
+	return append(order, goal)
+}
+
+// twoOpt reverses sections of the order when that's cheaper,
+// returning if it changed the order. See https://en.wikipedia.org/wiki/2-opt
+func twoOpt(costs [][]float64, order []int) bool {
+	changed := false
+	cost := orderCost(costs, order)
+
+	for i := 1; i < len(order)-2; i++ {
+		for j := i + 1; j < len(order)-1; j++ {
+			reverse(order[i : j+1])
+
+			// costs can be asymmetric, so the reversed
+			// section's cost has to be found again
+			if reversedCost := orderCost(costs, order); reversedCost < cost {
+				cost, changed = reversedCost, true
+			} else {
+				reverse(order[i : j+1])
+			}
+		}
+	}
+	return changed
+}
+
+// orOpt moves sections of up to 3 waypoints elsewhere in the
+// order when that's cheaper, returning if it changed the order
+func orOpt(costs [][]float64, order []int) bool {
+	changed := false
+	cost := orderCost(costs, order)
+	moved := make([]int, len(order))
+
+	for length := 1; length <= 3; length++ {
+		for i := 1; i+length < len(order); i++ {
+			for to := 1; to+length < len(order); to++ {
+				if to == i {
+					continue
+				}
+
+				moveSection(moved, order, i, length, to)
+				if movedCost := orderCost(costs, moved); movedCost < cost {
+					copy(order, moved)
+					cost, changed = movedCost, true
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// moveSection writes the order into out with the section
+// of the length at i moved to start at index to
+func moveSection(out, order []int, i, length, to int) {
+	rest := make([]int, 0, len(order)-length)
+	rest = append(rest, order[:i]...)
+	rest = append(rest, order[i+length:]...)
+
+	n := copy(out, rest[:to])
+	n += copy(out[n:], order[i:i+length])
+	copy(out[n:], rest[to:])
+}
+
+// orderCost returns the total cost of following the order
+func orderCost(costs [][]float64, order []int) float64 {
+	total := 0.0
+	for i := 1; i < len(order); i++ {
+		total += costs[order[i-1]][order[i]]
+	}
+	return total
+}
+
+func reverse(order []int) {
+	for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+		order[i], order[j] = order[j], order[i]
+	}
+}
@@ -0,0 +1,168 @@
+// Package tour plans routes that visit several waypoints on
+// the way from the start to the goal of an environment. The
+// cost between every pair of waypoints is found with A*, then
+// the order of the waypoints is solved as a traveling salesman
+// problem: exactly with dynamic programming for a few
+// waypoints, and with the 2-opt and or-opt local searches for
+// more. The paths between the waypoints are then stitched
+// together into a single path.
+//
+// See https://en.wikipedia.org/wiki/Travelling_salesman_problem
+package tour
+
+import (
+	"fmt"
+
+	"github.com/porgull/go-search/pkg/algorithms"
+	"github.com/porgull/go-search/pkg/environments"
+	"github.com/porgull/go-search/pkg/search"
+)
+
+const (
+	// DefaultExactLimit is the most waypoints
+	// that are ordered exactly by default
+	DefaultExactLimit = 12
+
+	// unreachable is the cost between two waypoints
+	// when there's no path between them, which is
+	// small enough that adding several can't overflow
+	unreachable = 1 << 40
+)
+
+// Tour is a path that visits every waypoint
+type Tour struct {
+	// Order contains the waypoints in the
+	// order that they are visited
+	Order []string
+
+	// Node is the goal node at the
+	// end of the stitched path
+	Node environments.Node
+
+	// Cost is the total cost of the path
+	Cost float64
+
+	// Exact is true if the order was solved exactly,
+	// rather than with the local searches
+	Exact bool
+
+	// Iterations is the total iterations
+	// of every A* search
+	Iterations int
+}
+
+// Solve finds a path from the start of the environment through
+// every waypoint to its goal, solving the order exactly if
+// there are at most exactLimit waypoints
+func Solve(e environments.WaypointEnvironment, waypoints []string, exactLimit int) (*Tour, error) {
+	start, goal := e.Endpoints()
+
+	// stops contains the start, then the
+	// waypoints, then the goal
+	stops := make([]string, 0, len(waypoints)+2)
+	stops = append(stops, start)
+	stops = append(stops, waypoints...)
+	stops = append(stops, goal)
+
+	p := &planner{
+		env:   e,
+		stops: stops,
+		paths: make(map[[2]int][]string, len(stops)*len(stops)),
+	}
+
+	costs, err := p.pairwiseCosts()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Tour{}
+	var order []int
+	if len(waypoints) <= exactLimit {
+		order, t.Exact = exactOrder(costs), true
+	} else {
+		order = heuristicOrder(costs)
+	}
+
+	if orderCost(costs, order) >= unreachable {
+		return nil, fmt.Errorf("there's no path that visits every waypoint")
+	}
+
+	names := []string{start}
+	for i := 1; i < len(order); i++ {
+		path := p.paths[[2]int{order[i-1], order[i]}]
+		names = append(names, path[1:]...)
+	}
+
+	t.Node, err = e.Path(names)
+	if err != nil {
+		return nil, fmt.Errorf("could not stitch paths together: %w", err)
+	}
+
+	t.Order = make([]string, 0, len(waypoints))
+	for _, stop := range order[1 : len(order)-1] {
+		t.Order = append(t.Order, stops[stop])
+	}
+	t.Cost = search.Result{Node: t.Node}.TotalCost()
+	t.Iterations = p.iterations
+	return t, nil
+}
+
+// planner finds the paths between the stops
+type planner struct {
+	env   environments.WaypointEnvironment
+	stops []string
+
+	// paths contains the names of the nodes on
+	// the path between each pair of stops
+	paths map[[2]int][]string
+
+	iterations int
+}
+
+// pairwiseCosts runs A* between every pair of stops,
+// except into the start and out of the goal, as they
+// are always first and last
+func (p *planner) pairwiseCosts() ([][]float64, error) {
+	costs := make([][]float64, len(p.stops))
+	for i := range p.stops {
+		costs[i] = make([]float64, len(p.stops))
+		for j := range p.stops {
+			if i == j || j == 0 || i == len(p.stops)-1 {
+				continue
+			}
+
+			var err error
+			if costs[i][j], err = p.cost(i, j); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return costs, nil
+}
+
+// cost returns the cost of the path between the stops,
+// which is unreachable if there's no path
+func (p *planner) cost(from, to int) (float64, error) {
+	between, err := p.env.Between(p.stops[from], p.stops[to])
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := algorithms.AStar{}.Run(search.Context{}, between)
+	p.iterations += result.Iterations
+	if err != nil {
+		return unreachable, nil
+	}
+
+	steps := make([]string, 0, 64)
+	for parent := result.Node; parent != nil; parent = parent.Parent() {
+		steps = append(steps, parent.Name())
+	}
+	for i, j := 0, len(steps)-1; i < j; i, j = i+1, j-1 {
+		steps[i], steps[j] = steps[j], steps[i]
+	}
+	p.paths[[2]int{from, to}] = steps
+
+	// the start node's cost isn't part of the path
+	return result.TotalCost() - environments.CostOf(between.Start()), nil
+}